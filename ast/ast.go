@@ -11,6 +11,19 @@ import (
 type Node interface {
 	TokenLiteral() string
 	Line() int
+	Pos() token.Span
+}
+
+// NodePos is embedded in every node to hold its location in the source code. it's set by the parser
+type NodePos struct {
+	Span token.Span
+}
+
+func (np *NodePos) Pos() token.Span {
+	return np.Span
+}
+func (np *NodePos) SetPos(s token.Span) {
+	np.Span = s
 }
 
 // each node type could be either a statement...
@@ -29,6 +42,7 @@ type Expression interface {
 
 // Tree is the root of every tree the parser will construct
 type Tree struct {
+	NodePos
 	Statements []Statement // consisting of struct that implement Statement interface
 }
 
@@ -47,6 +61,7 @@ func (t *Tree) Line() int { return 0 }
 
 // example of buat statement: buat x = 1 + 1;
 type BuatStatement struct {
+	NodePos
 	Token      token.Token // token.BUAT
 	Name       *Identifier // the ident name (x)
 	Expression Expression  // the value (1 + 1)
@@ -61,6 +76,7 @@ func (bs *BuatStatement) statementNode() {}
 func (bs *BuatStatement) Line() int      { return bs.Ln }
 
 type ReassignStatement struct {
+	NodePos
 	Token    token.Token
 	Ident    *Identifier
 	NewValue Expression
@@ -73,7 +89,23 @@ func (rs *ReassignStatement) TokenLiteral() string {
 func (rs *ReassignStatement) statementNode() {}
 func (rs *ReassignStatement) Line() int      { return rs.Ln }
 
+// example of index assignment: arr[0] = 1 or kamus["a"] = 1
+type IndexAssignStatement struct {
+	NodePos
+	Token    token.Token // the '='
+	Target   *IndexExpression
+	NewValue Expression
+	Ln       int
+}
+
+func (ia *IndexAssignStatement) TokenLiteral() string {
+	return ia.Token.Literal
+}
+func (ia *IndexAssignStatement) statementNode() {}
+func (ia *IndexAssignStatement) Line() int      { return ia.Ln }
+
 type KembalikanStatement struct {
+	NodePos
 	Token      token.Token
 	Expression Expression // the value expression that will be returned
 	Ln         int
@@ -87,6 +119,7 @@ func (bs *KembalikanStatement) Line() int      { return bs.Ln }
 
 // ExpressionStatement is statement that consist solely of one expression. it's a wrapper so that we could insert this in Tree Statements slice
 type ExpressionStatement struct {
+	NodePos
 	Token      token.Token // the first token in the ExpressionStatement
 	Expression Expression  // the struct that implement Expression interfae. e.g. Identifier, IntegerLiteral, etc...
 	Ln         int
@@ -99,6 +132,7 @@ func (ex *ExpressionStatement) statementNode() {}
 func (bs *ExpressionStatement) Line() int      { return bs.Ln }
 
 type BlockStatement struct {
+	NodePos
 	Token      token.Token
 	Statements []Statement
 	Ln         int
@@ -111,6 +145,7 @@ func (bs *BlockStatement) statementNode() {}
 func (bs *BlockStatement) Line() int      { return bs.Ln }
 
 type JikaStatement struct {
+	NodePos
 	Token        token.Token
 	Condition    Expression
 	JikaBlock    *BlockStatement
@@ -125,6 +160,7 @@ func (ie *JikaStatement) statementNode() {}
 func (bs *JikaStatement) Line() int      { return bs.Ln }
 
 type CetakStatement struct {
+	NodePos
 	Token      token.Token
 	Expression []Expression
 	Ln         int
@@ -136,11 +172,84 @@ func (cs *CetakStatement) TokenLiteral() string {
 func (cs *CetakStatement) statementNode() {}
 func (bs *CetakStatement) Line() int      { return bs.Ln }
 
+// example of selama statement: selama (x < 10) { x = x + 1; }
+type SelamaStatement struct {
+	NodePos
+	Token     token.Token // token.SELAMA
+	Condition Expression
+	Body      *BlockStatement
+	Ln        int
+}
+
+func (ss *SelamaStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+func (ss *SelamaStatement) statementNode() {}
+func (ss *SelamaStatement) Line() int      { return ss.Ln }
+
+// example of untuk statement: untuk (buat i = 0; i < 10; i = i + 1) { cetak(i); }
+type UntukStatement struct {
+	NodePos
+	Token     token.Token // token.UNTUK
+	Init      Statement   // could be nil
+	Condition Expression  // could be nil, which mean the loop run forever
+	Update    Statement   // could be nil
+	Body      *BlockStatement
+	Ln        int
+}
+
+func (us *UntukStatement) TokenLiteral() string {
+	return us.Token.Literal
+}
+func (us *UntukStatement) statementNode() {}
+func (us *UntukStatement) Line() int      { return us.Ln }
+
+// example of untuk dalam statement: untuk (x dalam [1, 2, 3]) { cetak(x); }
+type UntukDalamStatement struct {
+	NodePos
+	Token    token.Token // token.UNTUK
+	Ident    *Identifier // the ident that hold each element (x)
+	Iterable Expression  // the array or string that being iterated
+	Body     *BlockStatement
+	Ln       int
+}
+
+func (ud *UntukDalamStatement) TokenLiteral() string {
+	return ud.Token.Literal
+}
+func (ud *UntukDalamStatement) statementNode() {}
+func (ud *UntukDalamStatement) Line() int      { return ud.Ln }
+
+type HentiStatement struct {
+	NodePos
+	Token token.Token // token.HENTI
+	Ln    int
+}
+
+func (hs *HentiStatement) TokenLiteral() string {
+	return hs.Token.Literal
+}
+func (hs *HentiStatement) statementNode() {}
+func (hs *HentiStatement) Line() int      { return hs.Ln }
+
+type LanjutStatement struct {
+	NodePos
+	Token token.Token // token.LANJUT
+	Ln    int
+}
+
+func (ls *LanjutStatement) TokenLiteral() string {
+	return ls.Token.Literal
+}
+func (ls *LanjutStatement) statementNode() {}
+func (ls *LanjutStatement) Line() int      { return ls.Ln }
+
 /*******************************************
 *			EXPRESSION STRUCT			   *
 *******************************************/
 
 type Identifier struct {
+	NodePos
 	Token token.Token // token.IDENT
 	Value string      // the name of the variable, function name etc...
 	Ln    int
@@ -153,6 +262,7 @@ func (i *Identifier) expressionNode() {}
 func (bs *Identifier) Line() int      { return bs.Ln }
 
 type IntegerLiteral struct {
+	NodePos
 	Token token.Token
 	Value int // platform dependent. 32 size in 32 bits machine, 64 size in 64 bits machine
 	Ln    int
@@ -164,7 +274,21 @@ func (il *IntegerLiteral) TokenLiteral() string {
 func (il *IntegerLiteral) expressionNode() {}
 func (bs *IntegerLiteral) Line() int       { return bs.Ln }
 
+type FloatLiteral struct {
+	NodePos
+	Token token.Token
+	Value float64
+	Ln    int
+}
+
+func (fl *FloatLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+func (fl *FloatLiteral) expressionNode() {}
+func (fl *FloatLiteral) Line() int       { return fl.Ln }
+
 type PrefixExpression struct {
+	NodePos
 	Token    token.Token // the prefix token. e.g - or !
 	Operator string
 	Right    Expression // the expression struct that implement Expression interface
@@ -178,6 +302,7 @@ func (pe *PrefixExpression) expressionNode() {}
 func (bs *PrefixExpression) Line() int       { return bs.Ln }
 
 type InfixExpression struct {
+	NodePos
 	Token    token.Token
 	Left     Expression
 	Operator string
@@ -192,6 +317,7 @@ func (ie *InfixExpression) expressionNode() {}
 func (bs *InfixExpression) Line() int       { return bs.Ln }
 
 type BooleanLiteral struct {
+	NodePos
 	Token token.Token
 	Value bool
 	Ln    int
@@ -204,6 +330,7 @@ func (bl *BooleanLiteral) expressionNode() {}
 func (bs *BooleanLiteral) Line() int       { return bs.Ln }
 
 type FungsiExpression struct {
+	NodePos
 	Token  token.Token
 	Params []*Identifier
 	Body   *BlockStatement
@@ -217,6 +344,7 @@ func (fe *FungsiExpression) expressionNode() {}
 func (bs *FungsiExpression) Line() int       { return bs.Ln }
 
 type CallExpression struct {
+	NodePos
 	Token     token.Token // the '('
 	Function  Expression  // the ident to the function or FungsiExpression (literal)
 	Arguments []Expression
@@ -239,6 +367,7 @@ func (ce *CallExpression) expressionNode() {}
 func (bs *CallExpression) Line() int       { return bs.Ln }
 
 type StringLiteral struct {
+	NodePos
 	Token token.Token
 	Value string
 	Ln    int
@@ -252,21 +381,8 @@ func (s *StringLiteral) Line() int {
 	return s.Ln
 }
 
-type PanjangFungsi struct {
-	Token    token.Token
-	Argument Expression
-	Ln       int
-}
-
-func (pf *PanjangFungsi) TokenLiteral() string {
-	return pf.Token.Literal
-}
-func (pf *PanjangFungsi) expressionNode() {}
-func (pf *PanjangFungsi) Line() int {
-	return pf.Ln
-}
-
 type ArrayLiteral struct {
+	NodePos
 	Token    token.Token
 	Elements []Expression
 	// Index    Expression
@@ -282,6 +398,7 @@ func (a *ArrayLiteral) Line() int {
 }
 
 type IndexExpression struct {
+	NodePos
 	Token token.Token
 	Left  Expression // could be array literal, or identifier of array literal. maybe later i will add string too
 	Index Expression
@@ -295,3 +412,38 @@ func (ie *IndexExpression) Line() int {
 	return ie.Ln
 }
 func (ie *IndexExpression) expressionNode() {}
+
+// example of slice expression: arr[1:3], arr[:2] or arr[1:]. Start or End is nil if omitted
+type SliceExpression struct {
+	NodePos
+	Token token.Token // the '['
+	Left  Expression
+	Start Expression
+	End   Expression
+	Ln    int
+}
+
+func (se *SliceExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+func (se *SliceExpression) Line() int {
+	return se.Ln
+}
+func (se *SliceExpression) expressionNode() {}
+
+// example of kamus literal: {"nama": "kusmala", "umur": 1}
+type KamusLiteral struct {
+	NodePos
+	Token  token.Token // the '{'
+	Keys   []Expression
+	Values []Expression // Values[i] is the value of Keys[i]. we use two slices instead of map so the order is the same as in the source code
+	Ln     int
+}
+
+func (kl *KamusLiteral) TokenLiteral() string {
+	return kl.Token.Literal
+}
+func (kl *KamusLiteral) Line() int {
+	return kl.Ln
+}
+func (kl *KamusLiteral) expressionNode() {}
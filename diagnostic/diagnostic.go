@@ -0,0 +1,106 @@
+package diagnostic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vricap/kusmala/token"
+)
+
+// error code for parsing error start with P, runtime error start with R
+const (
+	TOKEN_TIDAK_DIHARAPKAN = "P001" // there's no way to parse the token here
+	TOKEN_HILANG           = "P002" // expecting a specific token, e.g ')'
+	EKSPRESI_KOSONG        = "P003" // expecting a value or expression
+	STRING_TIDAK_DITUTUP   = "P004"
+	ANGKA_TIDAK_VALID      = "P005" // number literal that doesn't fit, e.g 1e999
+
+	KESALAHAN_RUNTIME      = "R000" // runtime error without specific code
+	PENGENAL_TIDAK_DIKENAL = "R001"
+	KESALAHAN_TIPE         = "R002"
+	PEMBAGIAN_NOL          = "R003"
+	INDEX_DI_LUAR_BATAS    = "R004"
+	BUKAN_FUNGSI           = "R005"
+	JUMLAH_ARGUMEN         = "R006"
+	REKURSI_TERLALU_DALAM  = "R007"
+	ARGUMEN_TIDAK_VALID    = "R008" // the argument has the right type but the value can't be used, e.g negative count
+	KESALAHAN_INTERNAL     = "R999"
+)
+
+// Diagnostic is an error that know where it happen in the source code, so it could be rendered with the offending line
+type Diagnostic struct {
+	Code string
+	Msg  string
+	Span token.Span // zero if the position is unknown. the column is 0 if only the line is known
+	Hint string     // optional suggestion to fix the error
+}
+
+// Render the diagnostic with the source line and ^~~~ under the span. e.g:
+//
+//	ERROR[P002] di baris 1, kolom 12: Token selanjutnya mengharapkan ), tetapi menemukan ';'
+//	   1 | cetak(1 + 2;
+//	     |            ^
+//	     = saran: mungkin kurang ')'
+func (d *Diagnostic) Render(src string) string {
+	var b strings.Builder
+	switch {
+	case d.Span.IsZero():
+		fmt.Fprintf(&b, "ERROR[%s]: %s\n", d.Code, d.Msg)
+	case d.Span.Start.Col == 0: // only the line is known
+		fmt.Fprintf(&b, "ERROR[%s] di baris %d: %s\n", d.Code, d.Span.Start.Line, d.Msg)
+	default:
+		fmt.Fprintf(&b, "ERROR[%s] di baris %d, kolom %d: %s\n", d.Code, d.Span.Start.Line, d.Span.Start.Col, d.Msg)
+	}
+
+	line, ok := sourceLine(src, d.Span.Start.Line)
+	gutter := len(fmt.Sprint(d.Span.Start.Line)) + 3
+	if ok && !d.Span.IsZero() {
+		fmt.Fprintf(&b, "%*d | %s\n", gutter, d.Span.Start.Line, line)
+		if d.Span.Start.Col > 0 {
+			fmt.Fprintf(&b, "%*s | %s\n", gutter, "", underline(line, d.Span))
+		}
+	}
+	if d.Hint != "" {
+		fmt.Fprintf(&b, "%*s = saran: %s\n", gutter, "", d.Hint)
+	}
+	return b.String()
+}
+
+// sourceLine return the nth line (start from 1) of src
+func sourceLine(src string, n int) (string, bool) {
+	if n < 1 {
+		return "", false
+	}
+	lines := strings.Split(src, "\n")
+	if n > len(lines) {
+		return "", false
+	}
+	return strings.TrimRight(lines[n-1], "\r"), true
+}
+
+// underline put ^ under the first character of the span and ~ under the rest. span that continue to the next line is underlined until the end of the line
+func underline(line string, span token.Span) string {
+	runes := []rune(line)
+	start := span.Start.Col - 1
+	if start > len(runes) {
+		start = len(runes)
+	}
+	end := len(runes)
+	if span.End.Line == span.Start.Line && span.End.Col-1 < end {
+		end = span.End.Col - 1
+	}
+
+	var b strings.Builder
+	for _, r := range runes[:start] {
+		if r == '\t' { // keep the tab so the caret is aligned with the source line
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	b.WriteRune('^')
+	if end-start > 1 {
+		b.WriteString(strings.Repeat("~", end-start-1))
+	}
+	return b.String()
+}
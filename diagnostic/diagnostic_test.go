@@ -0,0 +1,85 @@
+package diagnostic
+
+import (
+	"testing"
+
+	"github.com/vricap/kusmala/token"
+)
+
+func span(line, startCol, endLine, endCol int) token.Span {
+	return token.Span{
+		Start: token.Position{Line: line, Col: startCol},
+		End:   token.Position{Line: endLine, Col: endCol},
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		diag   Diagnostic
+		expect string
+	}{
+		{
+			"caret at the column",
+			"buat x = 1;\ncetak(x + 2;",
+			Diagnostic{Code: TOKEN_HILANG, Msg: "kurang )", Span: span(2, 12, 2, 13), Hint: "mungkin kurang ')'"},
+			"ERROR[P002] di baris 2, kolom 12: kurang )\n" +
+				"   2 | cetak(x + 2;\n" +
+				"     |            ^\n" +
+				"     = saran: mungkin kurang ')'\n",
+		},
+		{
+			"underline the whole span",
+			"buat y = a + 1;",
+			Diagnostic{Code: PENGENAL_TIDAK_DIKENAL, Msg: "pengenal tidak diketahui", Span: span(1, 10, 1, 15)},
+			"ERROR[R001] di baris 1, kolom 10: pengenal tidak diketahui\n" +
+				"   1 | buat y = a + 1;\n" +
+				"     |          ^~~~~\n",
+		},
+		{
+			"tab is kept",
+			"jika (x) {\n\t\tcetak(1 / 0);\n}",
+			Diagnostic{Code: PEMBAGIAN_NOL, Msg: "pembagian dengan nol", Span: span(2, 9, 2, 14)},
+			"ERROR[R003] di baris 2, kolom 9: pembagian dengan nol\n" +
+				"   2 | \t\tcetak(1 / 0);\n" +
+				"     | \t\t      ^~~~~\n",
+		},
+		{
+			"span continue to the next line",
+			"buat s = \"abc\ndef\";",
+			Diagnostic{Code: STRING_TIDAK_DITUTUP, Msg: "string", Span: span(1, 10, 2, 5)},
+			"ERROR[P004] di baris 1, kolom 10: string\n" +
+				"   1 | buat s = \"abc\n" +
+				"     |          ^~~~\n",
+		},
+		{
+			"column counted in character",
+			"cetak(\"é\" + x);",
+			Diagnostic{Code: PENGENAL_TIDAK_DIKENAL, Msg: "x", Span: span(1, 13, 1, 14)},
+			"ERROR[R001] di baris 1, kolom 13: x\n" +
+				"   1 | cetak(\"é\" + x);\n" +
+				"     |             ^\n",
+		},
+		{
+			"only the line is known",
+			"buat x = 1;\nhenti;",
+			Diagnostic{Code: KESALAHAN_RUNTIME, Msg: "henti", Span: span(2, 0, 0, 0)},
+			"ERROR[R000] di baris 2: henti\n" +
+				"   2 | henti;\n",
+		},
+		{
+			"unknown position",
+			"1;",
+			Diagnostic{Code: KESALAHAN_RUNTIME, Msg: "gagal", Hint: "coba lagi"},
+			"ERROR[R000]: gagal\n" +
+				"     = saran: coba lagi\n",
+		},
+	}
+	for _, tt := range tests {
+		got := tt.diag.Render(tt.src)
+		if got != tt.expect {
+			t.Errorf("%s: wrong render.\nexpect:\n%s\ngot:\n%s", tt.name, tt.expect, got)
+		}
+	}
+}
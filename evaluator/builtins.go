@@ -0,0 +1,289 @@
+package evaluator
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vricap/kusmala/object"
+)
+
+// builtins is the registry of every function implemented in Go. adding a new builtin only need a new entry here, no change in lexer or parser
+var builtins = map[string]*object.Builtin{}
+
+func init() {
+	registerBuiltin("panjang", builtinPanjang)
+	registerBuiltin("masukan", builtinMasukan)
+	registerBuiltin("ke_angka", builtinKeAngka)
+	registerBuiltin("ke_teks", builtinKeTeks)
+	registerBuiltin("keluar", builtinKeluar)
+	registerBuiltin("tambah", builtinTambah)
+	registerBuiltin("hapus", builtinHapus)
+	registerBuiltin("peta", builtinPeta)
+	registerBuiltin("saring", builtinSaring)
+	registerBuiltin("lipat", builtinLipat)
+	registerBuiltin("urutkan", builtinUrutkan)
+}
+
+func registerBuiltin(name string, fn object.BuiltinFungsi) {
+	builtins[name] = &object.Builtin{Name: name, Fn: fn}
+}
+
+// checkArgsLen return error if the builtin is called with the wrong number of arguments
+func checkArgsLen(name string, args []object.Object, expect int, l int) *object.Error {
+	if len(args) != expect {
+		s := fmt.Sprintf("fungsi %s membutuhkan %d argumen namun menemukan %d argumen", name, expect, len(args))
+		return newArityError(s, name, l)
+	}
+	return nil
+}
+
+// panjang(x) return the length of string, array or kamus
+func builtinPanjang(env *object.Environment, l int, args ...object.Object) object.Object {
+	if err := checkArgsLen("panjang", args, 1, l); err != nil {
+		return err
+	}
+	arg := args[0]
+	var val int
+	switch a := arg.(type) {
+	case *object.Array:
+		val = len(a.El)
+	case *object.Kamus:
+		val = len(a.Pairs)
+	case *object.String:
+		val = utf8.RuneCountInString(a.Value) // count character, not byte
+	default:
+		return newTypeError("argumen panjang hanya menerima string, array atau kamus", arg.Inspect(), l)
+	}
+	return &object.Integer{Ln: l, Value: val}
+}
+
+// masukan(prompt) print the optional prompt and return one line read from stdin, without the trailing newline
+func builtinMasukan(env *object.Environment, l int, args ...object.Object) object.Object {
+	if len(args) > 1 {
+		s := fmt.Sprintf("fungsi masukan membutuhkan paling banyak 1 argumen namun menemukan %d argumen", len(args))
+		return newArityError(s, "masukan", l)
+	}
+	ctx := env.Context()
+	if len(args) == 1 {
+		prompt := args[0].Inspect()
+		if s, ok := args[0].(*object.String); ok {
+			prompt = s.Value
+		}
+		fmt.Fprint(ctx.Stdout, prompt)
+	}
+	line, err := ctx.Stdin.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return newError("tidak ada masukan yang dapat dibaca", "masukan", l)
+	}
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return &object.String{Ln: l, Value: line}
+}
+
+// ke_angka(x) convert string into integer or desimal. number is returned as it is
+func builtinKeAngka(env *object.Environment, l int, args ...object.Object) object.Object {
+	if err := checkArgsLen("ke_angka", args, 1, l); err != nil {
+		return err
+	}
+	switch a := args[0].(type) {
+	case *object.Integer, *object.Float:
+		return a
+	case *object.String:
+		s := strings.TrimSpace(a.Value)
+		if i, err := strconv.Atoi(s); err == nil {
+			return &object.Integer{Ln: l, Value: i}
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) { // ParseFloat also accept "nan" and "inf"
+			return &object.Float{Ln: l, Value: f}
+		}
+		return newError("teks tidak dapat diubah menjadi angka", a.Value, l)
+	default:
+		return newTypeError("argumen ke_angka hanya menerima string atau angka", args[0].Inspect(), l)
+	}
+}
+
+// ke_teks(x) return the string representation of any value
+func builtinKeTeks(env *object.Environment, l int, args ...object.Object) object.Object {
+	if err := checkArgsLen("ke_teks", args, 1, l); err != nil {
+		return err
+	}
+	if s, ok := args[0].(*object.String); ok {
+		return s
+	}
+	return &object.String{Ln: l, Value: args[0].Inspect()}
+}
+
+// keluar(kode) stop the program with the given status (0 if omitted). it can be called from anywhere, deep inside function or loop,
+// so instead of checking it at every step like error, it panic and get recovered in safeEvalStatement
+func builtinKeluar(env *object.Environment, l int, args ...object.Object) object.Object {
+	if len(args) > 1 {
+		s := fmt.Sprintf("fungsi keluar membutuhkan paling banyak 1 argumen namun menemukan %d argumen", len(args))
+		return newArityError(s, "keluar", l)
+	}
+	kode := 0
+	if len(args) == 1 {
+		i, ok := args[0].(*object.Integer)
+		if !ok {
+			return newTypeError("argumen keluar harus integer", args[0].Inspect(), l)
+		}
+		kode = i.Value
+	}
+	panic(&object.Keluar{Kode: kode, Ln: l})
+}
+
+// tambah(arr, x, ...) append the values to the end of arr in place and return arr
+func builtinTambah(env *object.Environment, l int, args ...object.Object) object.Object {
+	if len(args) < 2 {
+		s := fmt.Sprintf("fungsi tambah membutuhkan paling sedikit 2 argumen namun menemukan %d argumen", len(args))
+		return newArityError(s, "tambah", l)
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newTypeError("argumen pertama tambah harus array", args[0].Inspect(), l)
+	}
+	arr.El = append(arr.El, args[1:]...)
+	return arr
+}
+
+// hapus(arr) remove and return the last element. hapus(arr, i) remove and return the element at index i
+func builtinHapus(env *object.Environment, l int, args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		s := fmt.Sprintf("fungsi hapus membutuhkan 1 atau 2 argumen namun menemukan %d argumen", len(args))
+		return newArityError(s, "hapus", l)
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newTypeError("argumen pertama hapus harus array", args[0].Inspect(), l)
+	}
+	if len(arr.El) == 0 {
+		return newError("tidak dapat menghapus dari array kosong", arr.Inspect(), l)
+	}
+	i := len(arr.El) - 1
+	if len(args) == 2 {
+		var err *object.Error
+		if i, err = arrayIndex(arr, args[1], l); err != nil {
+			return err
+		}
+	}
+	val := arr.El[i]
+	arr.El = append(arr.El[:i], arr.El[i+1:]...)
+	return val
+}
+
+// checkCollectionArgs check the common arguments of higher-order builtins: array as the first argument and function as the second
+func checkCollectionArgs(name string, args []object.Object, expect int, l int) (*object.Array, *object.Error) {
+	if err := checkArgsLen(name, args, expect, l); err != nil {
+		return nil, err
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return nil, newTypeError(fmt.Sprintf("argumen pertama %s harus array", name), args[0].Inspect(), l)
+	}
+	switch args[1].(type) {
+	case *object.FungsiLiteral, *object.Builtin:
+		return arr, nil
+	default:
+		return nil, newTypeError(fmt.Sprintf("argumen kedua %s harus fungsi", name), args[1].Inspect(), l)
+	}
+}
+
+// peta(arr, f) return new array with f applied to each element
+func builtinPeta(env *object.Environment, l int, args ...object.Object) object.Object {
+	arr, err := checkCollectionArgs("peta", args, 2, l)
+	if err != nil {
+		return err
+	}
+	el := make([]object.Object, 0, len(arr.El))
+	for _, v := range arr.El {
+		val := applyFunction(args[1], []object.Object{v}, l, env)
+		if val.Type() == object.OBJECT_ERR {
+			return val
+		}
+		el = append(el, val)
+	}
+	return &object.Array{El: el, Ln: l}
+}
+
+// saring(arr, f) return new array with only the element where f return true
+func builtinSaring(env *object.Environment, l int, args ...object.Object) object.Object {
+	arr, err := checkCollectionArgs("saring", args, 2, l)
+	if err != nil {
+		return err
+	}
+	el := []object.Object{}
+	for _, v := range arr.El {
+		cond := applyFunction(args[1], []object.Object{v}, l, env)
+		if cond.Type() == object.OBJECT_ERR {
+			return cond
+		}
+		if condIsTrue(cond) {
+			el = append(el, v)
+		}
+	}
+	return &object.Array{El: el, Ln: l}
+}
+
+// lipat(arr, f, awal) combine the elements from left to right with f(akumulator, elemen), starting from awal
+func builtinLipat(env *object.Environment, l int, args ...object.Object) object.Object {
+	arr, err := checkCollectionArgs("lipat", args, 3, l)
+	if err != nil {
+		return err
+	}
+	acc := args[2]
+	for _, v := range arr.El {
+		acc = applyFunction(args[1], []object.Object{acc, v}, l, env)
+		if acc.Type() == object.OBJECT_ERR {
+			return acc
+		}
+	}
+	return acc
+}
+
+// urutkan(arr) return new sorted array in ascending order. urutkan(arr, f) use f(a, b) that return benar if a should be before b
+func builtinUrutkan(env *object.Environment, l int, args ...object.Object) object.Object {
+	var arr *object.Array
+	less := func(a, b object.Object) object.Object {
+		return evalInfixExpression("<", a, b, l)
+	}
+	if len(args) == 2 {
+		var err *object.Error
+		if arr, err = checkCollectionArgs("urutkan", args, 2, l); err != nil {
+			return err
+		}
+		less = func(a, b object.Object) object.Object {
+			return applyFunction(args[1], []object.Object{a, b}, l, env)
+		}
+	} else {
+		if err := checkArgsLen("urutkan", args, 1, l); err != nil {
+			return err
+		}
+		var ok bool
+		if arr, ok = args[0].(*object.Array); !ok {
+			return newTypeError("argumen pertama urutkan harus array", args[0].Inspect(), l)
+		}
+	}
+
+	el := make([]object.Object, len(arr.El))
+	copy(el, arr.El)
+	var err object.Object
+	sort.SliceStable(el, func(i, j int) bool {
+		if err != nil { // sort can't be stopped, so just skip the rest of the comparison
+			return false
+		}
+		res := less(el[i], el[j])
+		if res.Type() == object.OBJECT_ERR {
+			err = res
+			return false
+		}
+		return condIsTrue(res)
+	})
+	if err != nil {
+		return err
+	}
+	return &object.Array{El: el, Ln: l}
+}
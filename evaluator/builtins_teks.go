@@ -0,0 +1,207 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vricap/kusmala/diagnostic"
+	"github.com/vricap/kusmala/object"
+)
+
+// string library. every function take the string as the first argument, e.g pisah("a,b", ",")
+func init() {
+	registerBuiltin("pisah", builtinPisah)
+	registerBuiltin("gabung", builtinGabung)
+	registerBuiltin("pangkas", builtinPangkas)
+	registerBuiltin("berisi", builtinBerisi)
+	registerBuiltin("ganti", builtinGanti)
+	registerBuiltin("huruf_besar", builtinHurufBesar)
+	registerBuiltin("huruf_kecil", builtinHurufKecil)
+	registerBuiltin("ulangi", builtinUlangi)
+	registerBuiltin("indeks", builtinIndeks)
+	registerBuiltin("diawali", builtinDiawali)
+	registerBuiltin("diakhiri", builtinDiakhiri)
+}
+
+// stringArgs check the number of arguments and that all of them are string
+func stringArgs(name string, args []object.Object, expect int, l int) ([]string, *object.Error) {
+	if err := checkArgsLen(name, args, expect, l); err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(args))
+	for i := range args {
+		s, err := stringArg(name, args, i, l)
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = s
+	}
+	return strs, nil
+}
+
+func stringArg(name string, args []object.Object, i int, l int) (string, *object.Error) {
+	s, ok := args[i].(*object.String)
+	if !ok {
+		return "", newTypeError(fmt.Sprintf("argumen %s %s harus string", ordinal(i), name), args[i].Inspect(), l)
+	}
+	return s.Value, nil
+}
+
+// ordinal return the Indonesian ordinal of the argument position for the error message
+func ordinal(i int) string {
+	switch i {
+	case 0:
+		return "pertama"
+	case 1:
+		return "kedua"
+	case 2:
+		return "ketiga"
+	default:
+		return fmt.Sprintf("ke-%d", i+1)
+	}
+}
+
+func newString(s string, l int) *object.String {
+	return &object.String{Value: s, Ln: l}
+}
+
+func newBoolean(b bool, l int) *object.Boolean {
+	return &object.Boolean{Value: b, Ln: l}
+}
+
+// pisah(teks, pemisah) split the string into array of string
+func builtinPisah(env *object.Environment, l int, args ...object.Object) object.Object {
+	s, err := stringArgs("pisah", args, 2, l)
+	if err != nil {
+		return err
+	}
+	arr := &object.Array{Ln: l}
+	for _, p := range strings.Split(s[0], s[1]) {
+		arr.El = append(arr.El, newString(p, l))
+	}
+	return arr
+}
+
+// gabung(array, pemisah) join the elements into one string. non-string element is converted like ke_teks
+func builtinGabung(env *object.Environment, l int, args ...object.Object) object.Object {
+	if err := checkArgsLen("gabung", args, 2, l); err != nil {
+		return err
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return newTypeError("argumen pertama gabung harus array", args[0].Inspect(), l)
+	}
+	sep, err := stringArg("gabung", args, 1, l)
+	if err != nil {
+		return err
+	}
+	strs := make([]string, len(arr.El))
+	for i, el := range arr.El {
+		if s, ok := el.(*object.String); ok {
+			strs[i] = s.Value
+		} else {
+			strs[i] = el.Inspect()
+		}
+	}
+	return newString(strings.Join(strs, sep), l)
+}
+
+// pangkas(teks) remove the whitespace at the start and the end
+func builtinPangkas(env *object.Environment, l int, args ...object.Object) object.Object {
+	s, err := stringArgs("pangkas", args, 1, l)
+	if err != nil {
+		return err
+	}
+	return newString(strings.TrimSpace(s[0]), l)
+}
+
+// berisi(teks, bagian)
+func builtinBerisi(env *object.Environment, l int, args ...object.Object) object.Object {
+	s, err := stringArgs("berisi", args, 2, l)
+	if err != nil {
+		return err
+	}
+	return newBoolean(strings.Contains(s[0], s[1]), l)
+}
+
+// ganti(teks, lama, baru) replace every lama with baru
+func builtinGanti(env *object.Environment, l int, args ...object.Object) object.Object {
+	s, err := stringArgs("ganti", args, 3, l)
+	if err != nil {
+		return err
+	}
+	return newString(strings.ReplaceAll(s[0], s[1], s[2]), l)
+}
+
+func builtinHurufBesar(env *object.Environment, l int, args ...object.Object) object.Object {
+	s, err := stringArgs("huruf_besar", args, 1, l)
+	if err != nil {
+		return err
+	}
+	return newString(strings.ToUpper(s[0]), l)
+}
+
+func builtinHurufKecil(env *object.Environment, l int, args ...object.Object) object.Object {
+	s, err := stringArgs("huruf_kecil", args, 1, l)
+	if err != nil {
+		return err
+	}
+	return newString(strings.ToLower(s[0]), l)
+}
+
+// ulangi(teks, n)
+func builtinUlangi(env *object.Environment, l int, args ...object.Object) object.Object {
+	if err := checkArgsLen("ulangi", args, 2, l); err != nil {
+		return err
+	}
+	s, err := stringArg("ulangi", args, 0, l)
+	if err != nil {
+		return err
+	}
+	n, ok := args[1].(*object.Integer)
+	if !ok {
+		return newTypeError("argumen kedua ulangi harus integer", args[1].Inspect(), l)
+	}
+	if n.Value < 0 {
+		return newCodedError(diagnostic.ARGUMEN_TIDAK_VALID, "jumlah pengulangan tidak boleh negatif", n.Inspect(), l)
+	}
+	if len(s) > 0 && n.Value > maxTeks/len(s) { // Go run out of memory before we could recover it
+		return newCodedError(diagnostic.ARGUMEN_TIDAK_VALID, fmt.Sprintf("hasil ulangi melebihi %d byte", maxTeks), n.Inspect(), l)
+	}
+	return newString(strings.Repeat(s, n.Value), l)
+}
+
+// the longest string ulangi could create
+const maxTeks = 1 << 24
+
+// indeks(teks, bagian) return the character index of the first bagian, or -1 if not found
+func builtinIndeks(env *object.Environment, l int, args ...object.Object) object.Object {
+	s, err := stringArgs("indeks", args, 2, l)
+	if err != nil {
+		return err
+	}
+	i := strings.Index(s[0], s[1])
+	if i > 0 {
+		i = utf8.RuneCountInString(s[0][:i]) // byte offset to character offset, same as teks[i]
+	}
+	return &object.Integer{Value: i, Ln: l}
+}
+
+// diawali(teks, awalan)
+func builtinDiawali(env *object.Environment, l int, args ...object.Object) object.Object {
+	s, err := stringArgs("diawali", args, 2, l)
+	if err != nil {
+		return err
+	}
+	return newBoolean(strings.HasPrefix(s[0], s[1]), l)
+}
+
+// diakhiri(teks, akhiran)
+func builtinDiakhiri(env *object.Environment, l int, args ...object.Object) object.Object {
+	s, err := stringArgs("diakhiri", args, 2, l)
+	if err != nil {
+		return err
+	}
+	return newBoolean(strings.HasSuffix(s[0], s[1]), l)
+}
@@ -0,0 +1,59 @@
+package evaluator
+
+import "testing"
+
+func TestBuiltinTeks(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{`pisah("apel,jeruk,mangga", ",")`, "[apel, jeruk, mangga]"},
+		{`pisah("abc", "")`, "[a, b, c]"},
+		{`gabung(["a", "b", "c"], "-")`, "a-b-c"},
+		{`gabung([1, benar, "x"], ", ")`, "1, benar, x"},
+		{`gabung(pisah("satu dua", " "), "_")`, "satu_dua"},
+		{"pangkas(\"  halo dunia \n\t\")", "halo dunia"},
+		{`berisi("selamat pagi", "pagi")`, "benar"},
+		{`berisi("selamat pagi", "malam")`, "salah"},
+		{`ganti("saya suka kopi, kopi enak", "kopi", "teh")`, "saya suka teh, teh enak"},
+		{`huruf_besar("jakarta")`, "JAKARTA"},
+		{`huruf_kecil("BANDUNG")`, "bandung"},
+		{`huruf_besar("café")`, "CAFÉ"},
+		{`ulangi("ha", 3)`, "hahaha"},
+		{`ulangi("ha", 0)`, ""},
+		{`indeks("kusmala", "mala")`, "3"},
+		{`indeks("kusmala", "x")`, "-1"},
+		{`indeks("👋 halo", "halo")`, "2"},
+		{`buat s = "👋 halo"; s[indeks(s, "h")];`, "h"},
+		{`diawali("kusmala", "kus")`, "benar"},
+		{`diawali("kusmala", "mala")`, "salah"},
+		{`diakhiri("skrip.km", ".km")`, "benar"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		if eval.Inspect() != tt.expect {
+			t.Fatalf("%s: eval.Inspect() is not: '%s'. got: %s", tt.in, tt.expect, eval.Inspect())
+		}
+	}
+}
+
+func TestBuiltinTeksError(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{`pisah(1, ",")`, "ERROR di baris 1: argumen pertama pisah harus string dekat '1'"},
+		{`pisah("a", 1)`, "ERROR di baris 1: argumen kedua pisah harus string dekat '1'"},
+		{`ganti("a", "b", [])`, "ERROR di baris 1: argumen ketiga ganti harus string dekat '[]'"},
+		{`huruf_besar(benar)`, "ERROR di baris 1: argumen pertama huruf_besar harus string dekat 'benar'"},
+		{`berisi("a")`, "ERROR di baris 1: fungsi berisi membutuhkan 2 argumen namun menemukan 1 argumen dekat 'berisi'"},
+		{`gabung("a", ",")`, "ERROR di baris 1: argumen pertama gabung harus array dekat 'a'"},
+		{`gabung([], 1)`, "ERROR di baris 1: argumen kedua gabung harus string dekat '1'"},
+		{`ulangi("a", "2")`, "ERROR di baris 1: argumen kedua ulangi harus integer dekat '2'"},
+		{`ulangi("a", -1)`, "ERROR di baris 1: jumlah pengulangan tidak boleh negatif dekat '-1'"},
+		{`ulangi("x", 99999999999999)`, "ERROR di baris 1: hasil ulangi melebihi 16777216 byte dekat '99999999999999'"},
+	}
+	for _, tt := range test {
+		testErrorObject(t, testVal(tt.in), tt.expect)
+	}
+}
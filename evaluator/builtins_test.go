@@ -0,0 +1,252 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/vricap/kusmala/object"
+)
+
+func TestBuiltinPanjang(t *testing.T) {
+	test := []struct {
+		in     string
+		expect any
+	}{
+		{`panjang("")`, 0},
+		{`panjang("empat")`, 5},
+		{`panjang("é")`, 1},
+		{`panjang("Selamat pagi, dunia! 👋🇮🇩")`, 24},
+		{`panjang("bahasa Indonesia")`, 16},
+		{`panjang([1, 2, 3])`, 3},
+		{`panjang({"a": 1})`, 1},
+		{`panjang(1)`, "ERROR di baris 1: argumen panjang hanya menerima string, array atau kamus dekat '1'"},
+		{`panjang("a", "b")`, "ERROR di baris 1: fungsi panjang membutuhkan 1 argumen namun menemukan 2 argumen dekat 'panjang'"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		switch expect := tt.expect.(type) {
+		case int:
+			testIntegerObject(t, eval, expect)
+		case string:
+			testErrorObject(t, eval, expect)
+		}
+	}
+}
+
+func TestBuiltinFirstClass(t *testing.T) {
+	test := []struct {
+		in     string
+		expect int
+	}{
+		{`buat p = panjang; p("abc");`, 3},
+		{`buat terap = fungsi(f, x) { f(x); }; terap(panjang, [1, 2]);`, 2},
+		{`[panjang][0]("ab");`, 2},
+		// builtin could be shadowed
+		{`buat panjang = fungsi(x) { 42; }; panjang("abc");`, 42},
+		{`buat f = fungsi() { buat panjang = 1; panjang; }; f() + panjang("ab");`, 3},
+	}
+	for _, tt := range test {
+		testIntegerObject(t, testVal(tt.in), tt.expect)
+	}
+}
+
+func TestBuiltinInspect(t *testing.T) {
+	eval := testVal("panjang;")
+	b, ok := eval.(*object.Builtin)
+	if !ok {
+		t.Fatalf("eval is not *object.Builtin. got: %T", eval)
+	}
+	if b.Inspect() != "fungsi bawaan panjang" {
+		t.Fatalf("b.Inspect() is not 'fungsi bawaan panjang'. got: %s", b.Inspect())
+	}
+}
+
+func testErrorObject(t *testing.T, eval object.Object, expect string) {
+	e, ok := eval.(*object.Error)
+	if !ok {
+		t.Fatalf("object is not *object.Error. got: %T (%s)", eval, eval.Inspect())
+	}
+	if e.Inspect() != expect {
+		t.Fatalf("e.Inspect() is not: '%s'. got: %s", expect, e.Inspect())
+	}
+}
+
+func TestBuiltinMasukan(t *testing.T) {
+	test := []struct {
+		in     string
+		stdin  string
+		out    string
+		expect string
+	}{
+		{`buat nama = masukan("Nama: "); cetak("Halo", nama);`, "Budi\n", "Nama: Halo Budi \n", "Budi"},
+		{`buat a = masukan(); buat b = masukan(); cetak(b, a);`, "satu\r\ndua\n", "dua satu \n", "satu"},
+		{`masukan();`, "tanpa baris baru", "", "tanpa baris baru"},
+	}
+	for _, tt := range test {
+		out, res := testOutput(tt.in, tt.stdin)
+		if res.Err != nil {
+			t.Fatalf("unexpected error: %s", res.Err.Inspect())
+		}
+		if out != tt.out {
+			t.Fatalf("output is not %q. got: %q", tt.out, out)
+		}
+		testStringObject(t, res.Values[0], tt.expect)
+	}
+
+	_, res := testOutput(`masukan();`, "")
+	if res.Err == nil {
+		t.Fatalf("expected error on empty stdin")
+	}
+	testErrorObject(t, res.Err, "ERROR di baris 1: tidak ada masukan yang dapat dibaca dekat 'masukan'")
+}
+
+func TestBuiltinKonversi(t *testing.T) {
+	test := []struct {
+		in     string
+		expect any
+	}{
+		{`ke_angka("12") + 1`, 13},
+		{`ke_angka(" 7 ")`, 7},
+		{`ke_angka(5)`, 5},
+		{`ke_angka("2.5")`, 2.5},
+		{`ke_angka("dua")`, "ERROR di baris 1: teks tidak dapat diubah menjadi angka dekat 'dua'"},
+		{`ke_angka("nan")`, "ERROR di baris 1: teks tidak dapat diubah menjadi angka dekat 'nan'"},
+		{`ke_angka("inf")`, "ERROR di baris 1: teks tidak dapat diubah menjadi angka dekat 'inf'"},
+		{`ke_angka("-Infinity")`, "ERROR di baris 1: teks tidak dapat diubah menjadi angka dekat '-Infinity'"},
+		{`ke_angka("1e999")`, "ERROR di baris 1: teks tidak dapat diubah menjadi angka dekat '1e999'"},
+		{`ke_angka(benar)`, "ERROR di baris 1: argumen ke_angka hanya menerima string atau angka dekat 'benar'"},
+		{`ke_teks(12) + "3"`, "123"},
+		{`ke_teks([1, 2])`, "[1, 2]"},
+		{`ke_teks("teks")`, "teks"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		switch expect := tt.expect.(type) {
+		case int:
+			testIntegerObject(t, eval, expect)
+		case float64:
+			testFloatObject(t, eval, expect)
+		case string:
+			if _, ok := eval.(*object.Error); ok {
+				testErrorObject(t, eval, expect)
+			} else {
+				testStringObject(t, eval, expect)
+			}
+		}
+	}
+}
+
+func TestBuiltinKeluar(t *testing.T) {
+	test := []struct {
+		in     string
+		out    string
+		expect int
+	}{
+		{`cetak("a"); keluar(3); cetak("b");`, "a \n", 3},
+		{`keluar();`, "", 0},
+		// keluar from deep inside function and loop
+		{`buat f = fungsi(n) { selama(benar) { jika(n == 0) { keluar(7); } n = n - 1; } }; f(3); cetak("tidak");`, "", 7},
+	}
+	for _, tt := range test {
+		out, res := testOutput(tt.in, "")
+		if res.Err != nil {
+			t.Fatalf("unexpected error: %s", res.Err.Inspect())
+		}
+		if res.Keluar == nil {
+			t.Fatalf("res.Keluar is nil for: %s", tt.in)
+		}
+		if res.Keluar.Kode != tt.expect {
+			t.Fatalf("res.Keluar.Kode is not %d. got: %d", tt.expect, res.Keluar.Kode)
+		}
+		if out != tt.out {
+			t.Fatalf("output is not %q. got: %q", tt.out, out)
+		}
+	}
+
+	testErrorObject(t, testVal(`keluar("1")`), "ERROR di baris 1: argumen keluar harus integer dekat '1'")
+	testErrorObject(t, testVal(`keluar(1, 2)`), "ERROR di baris 1: fungsi keluar membutuhkan paling banyak 1 argumen namun menemukan 2 argumen dekat 'keluar'")
+}
+
+func TestBuiltinTambahHapus(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{`buat a = [1]; tambah(a, 2, 3); a;`, "[1, 2, 3]"},
+		{`tambah([], "x");`, "[x]"},
+		{`buat a = [1, 2, 3]; hapus(a);`, "3"},
+		{`buat a = [1, 2, 3]; hapus(a); a;`, "[1, 2]"},
+		{`buat a = [1, 2, 3]; hapus(a, 0); a;`, "[2, 3]"},
+		{`buat a = []; untuk(buat i = 0; i < 3; i = i + 1) { tambah(a, i * i); } a;`, "[0, 1, 4]"},
+		{`tambah([1]);`, "ERROR di baris 1: fungsi tambah membutuhkan paling sedikit 2 argumen namun menemukan 1 argumen dekat 'tambah'"},
+		{`tambah(1, 2);`, "ERROR di baris 1: argumen pertama tambah harus array dekat '1'"},
+		{`hapus([]);`, "ERROR di baris 1: tidak dapat menghapus dari array kosong dekat '[]'"},
+		{`hapus([1], 1);`, "ERROR di baris 1: argumen index melebihi panjang array dekat '[1]'"},
+		{`hapus("a");`, "ERROR di baris 1: argumen pertama hapus harus array dekat 'a'"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		if eval.Inspect() != tt.expect {
+			t.Fatalf("eval.Inspect() is not: '%s'. got: %s", tt.expect, eval.Inspect())
+		}
+	}
+}
+
+func TestBuiltinHigherOrder(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{`peta([1, 2, 3], fungsi(x) { x * 2 });`, "[2, 4, 6]"},
+		{`peta([], fungsi(x) { x });`, "[]"},
+		{`peta(["ab", "c"], panjang);`, "[2, 1]"},
+		{`buat f = fungsi(x) { jika(x > 1) { kembalikan "besar"; } kembalikan "kecil"; }; peta([1, 2], f);`, "[kecil, besar]"},
+		{`saring([1, 2, 3, 4], fungsi(x) { x % 2 == 0 });`, "[2, 4]"},
+		{`lipat([1, 2, 3, 4], fungsi(acc, x) { acc + x }, 0);`, "10"},
+		{`lipat([], fungsi(acc, x) { acc + x }, 5);`, "5"},
+		{`lipat(["a", "b"], fungsi(acc, x) { acc + x }, "");`, "ab"},
+		{`urutkan([3, 1, 2]);`, "[1, 2, 3]"},
+		{`urutkan([2.5, 1, 2]);`, "[1, 2, 2.5]"},
+		{`urutkan(["pisang", "apel", "ceri"]);`, "[apel, ceri, pisang]"},
+		{`urutkan([3, 1, 2], fungsi(a, b) { a > b });`, "[3, 2, 1]"},
+		// urutkan doesn't change the original array and it's stable
+		{`buat a = [3, 1, 2]; urutkan(a); a;`, "[3, 1, 2]"},
+		{`buat a = [[2, "a"], [1, "b"], [2, "c"]]; peta(urutkan(a, fungsi(x, y) { x[0] < y[0] }), fungsi(x) { x[1] });`, "[b, a, c]"},
+		// closure inside callback
+		{`buat n = 10; peta([1, 2], fungsi(x) { x + n });`, "[11, 12]"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		if eval.Inspect() != tt.expect {
+			t.Fatalf("eval.Inspect() is not: '%s'. got: %s", tt.expect, eval.Inspect())
+		}
+	}
+}
+
+func TestBuiltinHigherOrderError(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+		stack  []string
+	}{
+		{"buat rusak = fungsi(x) {\n x / 0;\n};\npeta([1], rusak);", "ERROR di baris 2: pembagian dengan nol dekat '1 / 0'", []string{"di fungsi rusak (baris 2) dipanggil dari baris 4"}},
+		{"saring([1], fungsi(x) { y });", "ERROR di baris 1: pengenal tidak diketahui dekat 'y'", []string{"di fungsi anonim (baris 1) dipanggil dari baris 1"}},
+		{"lipat([1], fungsi(x) { x }, 0);", "ERROR di baris 1: fungsi membutuhkan 1 parameter namun menemukan 2 argumen dekat 'anonim'", nil},
+		{"urutkan([1, \"a\"]);", "ERROR di baris 1: kesalahan tipe dekat 'a < 1'", nil},
+		{"peta(1, panjang);", "ERROR di baris 1: argumen pertama peta harus array dekat '1'", nil},
+		{"saring([1], 1);", "ERROR di baris 1: argumen kedua saring harus fungsi dekat '1'", nil},
+		{"lipat([1], panjang);", "ERROR di baris 1: fungsi lipat membutuhkan 3 argumen namun menemukan 2 argumen dekat 'lipat'", nil},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		testErrorObject(t, eval, tt.expect)
+		stack := eval.(*object.Error).StackTrace()
+		if len(stack) != len(tt.stack) {
+			t.Fatalf("stack trace is not %v. got: %v", tt.stack, stack)
+		}
+		for i := range stack {
+			if stack[i] != tt.stack[i] {
+				t.Fatalf("stack[%d] is not %q. got: %q", i, tt.stack[i], stack[i])
+			}
+		}
+	}
+}
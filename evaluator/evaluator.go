@@ -2,32 +2,98 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/vricap/kusmala/ast"
+	"github.com/vricap/kusmala/diagnostic"
 	"github.com/vricap/kusmala/object"
+	"github.com/vricap/kusmala/token"
 )
 
-func Eval(tree *ast.Tree, env *object.Environment) []object.Object {
-	var evals []object.Object
+// Result is the outcome of evaluating the whole tree. the evaluator doesn't print anything by itself, it's up to the caller (file or repl) to decide how to render it
+type Result struct {
+	Values []object.Object // the value of each top-level statement that run successfully
+	Err    *object.Error   // the first runtime error. nil if the program run until the end
+	Keluar *object.Keluar  // set if the program called keluar(kode). the caller decide what to do with the status
+}
+
+func Eval(tree *ast.Tree, env *object.Environment) *Result {
+	res := &Result{}
 	for _, s := range tree.Statements {
-		eval := evalStatement(s, env)
+		eval := safeEvalStatement(s, env)
+		switch v := eval.(type) {
+		case *object.Henti, *object.Lanjut:
+			eval = outsideLoopError(v)
+		}
 		if err, ok := eval.(*object.Error); ok {
-			fmt.Println("\t", err.Inspect())
-			break // TODO: remove this when running test case
+			res.Err = err // the program stop at the first error
+			break
+		}
+		if k, ok := eval.(*object.Keluar); ok {
+			res.Keluar = k
+			break
 		}
 		if v, ok := eval.(*object.Kembalikan); ok {
-			evals = append(evals, v.Value)
+			res.Values = append(res.Values, v.Value)
 			continue
 		}
-		evals = append(evals, eval)
+		res.Values = append(res.Values, eval)
 	}
-	return evals
+	return res
+}
+
+// safeEvalStatement is the boundary between kusmala and Go. any Go panic that happen while evaluating the statement is turned into kusmala error, so a bug in the interpreter doesn't kill the whole REPL session
+func safeEvalStatement(stmt ast.Statement, env *object.Environment) (eval object.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			if k, ok := r.(*object.Keluar); ok { // keluar unwind the whole program, see builtinKeluar
+				eval = k
+				return
+			}
+			eval = newCodedError(diagnostic.KESALAHAN_INTERNAL, "kesalahan internal interpreter", r, stmt.Line())
+		}
+	}()
+	return evalStatement(stmt, env)
 }
 
 func evalStatement(stmt ast.Statement, env *object.Environment) object.Object {
+	return withPos(evalStatementNode(stmt, env), stmt)
+}
+
+func evalExpression(expr ast.Expression, env *object.Environment) object.Object {
+	return withPos(evalExpressionNode(expr, env), expr)
+}
+
+// outsideLoopError is for henti or lanjut that unwind until the function body or the top level. the error point to the statement itself, not to where it end up
+func outsideLoopError(obj object.Object) *object.Error {
+	err := newError("statement hanya boleh berada di dalam perulangan", obj.Inspect(), obj.Line())
+	switch v := obj.(type) {
+	case *object.Henti:
+		err.Span = v.Span
+	case *object.Lanjut:
+		err.Span = v.Span
+	}
+	return err
+}
+
+// withPos give the error the position of the node. the innermost node that produce the error set it first, so the outer one doesn't overwrite it
+func withPos(obj object.Object, node ast.Node) object.Object {
+	if err, ok := obj.(*object.Error); ok && err.Span.IsZero() {
+		err.Span = node.Pos()
+	}
+	return obj
+}
+
+func evalStatementNode(stmt ast.Statement, env *object.Environment) object.Object {
 	switch s := stmt.(type) {
 	case *ast.BuatStatement:
 		val := evalExpression(s.Expression, env)
+		if val.Type() == object.OBJECT_ERR {
+			return val
+		}
+		if fl, ok := val.(*object.FungsiLiteral); ok && fl.Name == "" {
+			fl.Name = s.Name.Value // name the function for the stack trace
+		}
 		env.Set(s.Name.Value, val)
 		return val
 	case *ast.JikaStatement:
@@ -40,26 +106,52 @@ func evalStatement(stmt ast.Statement, env *object.Environment) object.Object {
 		return evalCetakStatement(s, env)
 	case *ast.ReassignStatement:
 		return evalReassignStatement(s, env, s.Ln)
+	case *ast.IndexAssignStatement:
+		return evalIndexAssignStatement(s, env)
 	case *ast.KembalikanStatement:
 		return evalKembalikanStatement(s, env)
+	case *ast.SelamaStatement:
+		return evalSelamaStatement(s, env)
+	case *ast.UntukStatement:
+		return evalUntukStatement(s, env)
+	case *ast.UntukDalamStatement:
+		return evalUntukDalamStatement(s, env)
+	case *ast.HentiStatement:
+		return &object.Henti{Ln: s.Ln, Span: s.Pos()}
+	case *ast.LanjutStatement:
+		return &object.Lanjut{Ln: s.Ln, Span: s.Pos()}
 	default:
 		return newError("statement tidak diketahui atau tidak ditempatnya", s.TokenLiteral(), s.Line())
 	}
 }
 
-func evalExpression(expr ast.Expression, env *object.Environment) object.Object {
+func evalExpressionNode(expr ast.Expression, env *object.Environment) object.Object {
 	switch e := expr.(type) {
 	case *ast.Identifier:
 		return evalIdentifier(e, env)
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: e.Value, Ln: e.Ln}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: e.Value, Ln: e.Ln}
 	case *ast.PrefixExpression:
 		right := evalExpression(e.Right, env)
+		if right.Type() == object.OBJECT_ERR {
+			return right
+		}
 		return evalPrefixExpression(e.Operator, right)
 	case *ast.InfixExpression:
+		if e.Token.Type == token.DAN || e.Token.Type == token.ATAU {
+			return evalLogicalExpression(e, env)
+		}
 		left := evalExpression(e.Left, env)
+		if left.Type() == object.OBJECT_ERR {
+			return left
+		}
 		right := evalExpression(e.Right, env)
-		return evalInfixExpression(e.Operator, left, right)
+		if right.Type() == object.OBJECT_ERR {
+			return right
+		}
+		return evalInfixExpression(e.Operator, left, right, e.Ln)
 	case *ast.BooleanLiteral:
 		return &object.Boolean{Value: e.Value, Ln: e.Ln}
 	case *ast.FungsiExpression:
@@ -72,10 +164,10 @@ func evalExpression(expr ast.Expression, env *object.Environment) object.Object
 		return runFunction(fn, e, env)
 	case *ast.StringLiteral:
 		return &object.String{Value: e.Value, Ln: e.Ln}
-	case *ast.PanjangFungsi:
-		return evalPanjangFungsi(e, e.Ln, env)
 	case *ast.ArrayLiteral:
 		return evalArray(e, e.Ln, env)
+	case *ast.KamusLiteral:
+		return evalKamus(e, env)
 	case *ast.IndexExpression:
 		left := evalExpression(e.Left, env)
 		if left.Type() == object.OBJECT_ERR {
@@ -86,6 +178,8 @@ func evalExpression(expr ast.Expression, env *object.Environment) object.Object
 			return index
 		}
 		return evalIndexExpression(left, index, e.Ln, env)
+	case *ast.SliceExpression:
+		return evalSliceExpression(e, env)
 	default:
 		return newError("ekspresi tidak diketahui atau tidak ditempatnya", e.TokenLiteral(), e.Line())
 	}
@@ -105,16 +199,19 @@ func evalPrefixExpression(op string, right object.Object) object.Object {
 			return &object.Boolean{Value: false}
 		}
 	case "-":
-		if right.Type() != object.OBJECT_INTEGER {
-			return newError("operator tidak didukung", fmt.Sprintf("%s%s", op, right.Inspect()), right.Line())
+		switch r := right.(type) {
+		case *object.Integer:
+			return &object.Integer{Value: -(r.Value)}
+		case *object.Float:
+			return &object.Float{Value: -(r.Value)}
+		default:
+			return newTypeError("operator tidak didukung", fmt.Sprintf("%s%s", op, right.Inspect()), right.Line())
 		}
-		i := right.(*object.Integer)
-		return &object.Integer{Value: -(i.Value)}
 	}
-	return newError("operator tidak didukung", fmt.Sprintf("%s%s", op, right.Inspect()), right.Line())
+	return newTypeError("operator tidak didukung", fmt.Sprintf("%s%s", op, right.Inspect()), right.Line())
 }
 
-func evalInfixExpression(op string, left object.Object, right object.Object) object.Object {
+func evalInfixExpression(op string, left object.Object, right object.Object, ln int) object.Object {
 	if k, ok := left.(*object.Kembalikan); ok {
 		left = k.Value
 	}
@@ -123,19 +220,50 @@ func evalInfixExpression(op string, left object.Object, right object.Object) obj
 	}
 	// TODO: add support for inter-expression between boolean and integer e.g: 1 == benar
 	if left.Type() == object.OBJECT_INTEGER && right.Type() == object.OBJECT_INTEGER {
-		return evalInfixIntegerExpression(op, left, right)
+		return evalInfixIntegerExpression(op, left, right, ln)
+	}
+	// if one of the operand is float, the integer one is promoted to float
+	if isNumber(left) && isNumber(right) {
+		return evalInfixFloatExpression(op, left, right, ln)
 	}
 	if left.Type() == object.OBJECT_BOOLEAN && right.Type() == object.OBJECT_BOOLEAN {
-		return evalInifxBooelanExpression(op, left, right)
+		return evalInifxBooelanExpression(op, left, right, ln)
 	}
 
 	if left.Type() == object.OBJECT_STRING && right.Type() == object.OBJECT_STRING {
-		return evalInifxStringExpression(op, left, right)
+		return evalInifxStringExpression(op, left, right, ln)
+	}
+	err := newTypeError("kesalahan tipe", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), ln)
+	err.Hint = "kedua operan harus bertipe sama, gunakan ke_teks atau ke_angka untuk mengubah tipe"
+	return err
+}
+
+// && and || is short-circuiting, so the right side is only evaluated when the left side doesn't decide the result
+func evalLogicalExpression(e *ast.InfixExpression, env *object.Environment) object.Object {
+	left := evalExpression(e.Left, env)
+	if k, ok := left.(*object.Kembalikan); ok {
+		left = k.Value
+	}
+	if left.Type() == object.OBJECT_ERR {
+		return left
+	}
+	if e.Token.Type == token.DAN && !condIsTrue(left) {
+		return &object.Boolean{Value: false, Ln: e.Ln}
+	}
+	if e.Token.Type == token.ATAU && condIsTrue(left) {
+		return &object.Boolean{Value: true, Ln: e.Ln}
+	}
+	right := evalExpression(e.Right, env)
+	if k, ok := right.(*object.Kembalikan); ok {
+		right = k.Value
+	}
+	if right.Type() == object.OBJECT_ERR {
+		return right
 	}
-	return newError("kesalahan tipe", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), left.Line())
+	return &object.Boolean{Value: condIsTrue(right), Ln: e.Ln}
 }
 
-func evalInifxBooelanExpression(op string, left object.Object, right object.Object) object.Object {
+func evalInifxBooelanExpression(op string, left object.Object, right object.Object, ln int) object.Object {
 	l := left.(*object.Boolean).Value
 	r := right.(*object.Boolean).Value
 	switch op {
@@ -143,14 +271,19 @@ func evalInifxBooelanExpression(op string, left object.Object, right object.Obje
 		return &object.Boolean{Value: l == r}
 	case "!=":
 		return &object.Boolean{Value: l != r}
+	case "<", ">", "<=", ">=": // salah is ordered before benar
+		return evalInfixIntegerExpression(op, &object.Integer{Value: boolToInt(l)}, &object.Integer{Value: boolToInt(r)}, ln)
 	default:
-		return newError("operator tidak didukung", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), left.Line())
+		return newTypeError("operator tidak didukung", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), ln)
 	}
 }
 
-func evalInfixIntegerExpression(op string, left object.Object, right object.Object) object.Object {
+func evalInfixIntegerExpression(op string, left object.Object, right object.Object, ln int) object.Object {
 	l := left.(*object.Integer).Value
 	r := right.(*object.Integer).Value
+	if r == 0 && isDivision(op) {
+		return newCodedError(diagnostic.PEMBAGIAN_NOL, "pembagian dengan nol", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), ln)
+	}
 	switch op {
 	case "+":
 		return &object.Integer{Value: l + r}
@@ -158,42 +291,149 @@ func evalInfixIntegerExpression(op string, left object.Object, right object.Obje
 		return &object.Integer{Value: l - r}
 	case "*":
 		return &object.Integer{Value: l * r}
-	case "/":
+	case "/", "div": // integer divided by integer stay integer. use desimal operand (10 / 4.0) to get the fraction
 		return &object.Integer{Value: l / r}
+	case "%":
+		return &object.Integer{Value: l % r}
+	case "**":
+		if r < 0 { // 2 ** -1 is 0.5
+			return &object.Float{Value: math.Pow(float64(l), float64(r))}
+		}
+		return &object.Integer{Value: intPow(l, r)}
 	case "<":
 		return &object.Boolean{Value: l < r}
 	case ">":
 		return &object.Boolean{Value: l > r}
+	case "<=":
+		return &object.Boolean{Value: l <= r}
+	case ">=":
+		return &object.Boolean{Value: l >= r}
 	case "==":
 		return &object.Boolean{Value: l == r}
 	case "!=":
 		return &object.Boolean{Value: l != r}
 	default:
-		return newError("operator tidak didukung", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), left.Line())
+		return newTypeError("operator tidak didukung", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), ln)
 	}
 }
 
-func evalInifxStringExpression(op string, left object.Object, right object.Object) object.Object {
+func evalInfixFloatExpression(op string, left object.Object, right object.Object, ln int) object.Object {
+	l := toFloat(left)
+	r := toFloat(right)
+	if r == 0 && isDivision(op) {
+		return newCodedError(diagnostic.PEMBAGIAN_NOL, "pembagian dengan nol", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), ln)
+	}
+	switch op {
+	case "+":
+		return &object.Float{Value: l + r}
+	case "-":
+		return &object.Float{Value: l - r}
+	case "*":
+		return &object.Float{Value: l * r}
+	case "/":
+		return &object.Float{Value: l / r}
+	case "div":
+		return &object.Float{Value: math.Trunc(l / r)}
+	case "%":
+		return &object.Float{Value: math.Mod(l, r)}
+	case "**":
+		return &object.Float{Value: math.Pow(l, r)}
+	case "<":
+		return &object.Boolean{Value: l < r}
+	case ">":
+		return &object.Boolean{Value: l > r}
+	case "<=":
+		return &object.Boolean{Value: l <= r}
+	case ">=":
+		return &object.Boolean{Value: l >= r}
+	case "==":
+		return &object.Boolean{Value: l == r}
+	case "!=":
+		return &object.Boolean{Value: l != r}
+	default:
+		return newTypeError("operator tidak didukung", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), ln)
+	}
+}
+
+// intPow use exponentiation by squaring so the result stay an exact integer
+func intPow(base int, exp int) int {
+	result := 1
+	for exp > 0 {
+		if exp%2 == 1 {
+			result *= base
+		}
+		base *= base
+		exp /= 2
+	}
+	return result
+}
+
+func isDivision(op string) bool {
+	return op == "/" || op == "div" || op == "%"
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.OBJECT_INTEGER || obj.Type() == object.OBJECT_FLOAT
+}
+
+func toFloat(obj object.Object) float64 {
+	switch n := obj.(type) {
+	case *object.Integer:
+		return float64(n.Value)
+	case *object.Float:
+		return n.Value
+	}
+	return 0
+}
+
+func evalInifxStringExpression(op string, left object.Object, right object.Object, ln int) object.Object {
 	l := left.(*object.String).Value
 	r := right.(*object.String).Value
 	switch op {
 	case "+":
 		return &object.String{Value: l + r} // string concatenation
+	// string comparison is lexicographic, byte by byte
+	case "<":
+		return &object.Boolean{Value: l < r}
+	case ">":
+		return &object.Boolean{Value: l > r}
+	case "<=":
+		return &object.Boolean{Value: l <= r}
+	case ">=":
+		return &object.Boolean{Value: l >= r}
+	case "==":
+		return &object.Boolean{Value: l == r}
+	case "!=":
+		return &object.Boolean{Value: l != r}
 	default:
-		return newError("operator tidak didukung", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), left.Line())
+		return newTypeError("operator tidak didukung", fmt.Sprintf("%v %v %v", left.Inspect(), op, right.Inspect()), ln)
 	}
 }
 
 func evalIdentifier(i *ast.Identifier, env *object.Environment) object.Object {
 	val, ok := env.Get(i.Value)
-	if !ok {
-		return newError("pengenal tidak diketahui", i.Value, i.Ln)
+	if ok {
+		return val
 	}
-	return val
+	// builtin is looked up last, so user could shadow it with buat
+	if b, ok := builtins[i.Value]; ok {
+		return b
+	}
+	return newCodedError(diagnostic.PENGENAL_TIDAK_DIKENAL, "pengenal tidak diketahui", i.Value, i.Ln)
 }
 
 func evalJikaStatement(jk *ast.JikaStatement, env *object.Environment) object.Object {
 	cond := evalExpression(jk.Condition, env)
+	if cond.Type() == object.OBJECT_ERR {
+		return cond
+	}
 	// newChildEnv := object.NewChildEnv(env) // TODO: this fuck recursive function
 	if condIsTrue(cond) {
 		return evalStatement(jk.JikaBlock, env)
@@ -204,6 +444,96 @@ func evalJikaStatement(jk *ast.JikaStatement, env *object.Environment) object.Ob
 	}
 }
 
+func evalSelamaStatement(ss *ast.SelamaStatement, env *object.Environment) object.Object {
+	for {
+		cond := evalExpression(ss.Condition, env)
+		if cond.Type() == object.OBJECT_ERR {
+			return cond
+		}
+		if !condIsTrue(cond) {
+			break
+		}
+		if eval, stop := evalLoopBody(ss.Body, env); stop {
+			return eval
+		}
+	}
+	return &object.Nil{}
+}
+
+func evalUntukStatement(us *ast.UntukStatement, env *object.Environment) object.Object {
+	if us.Init != nil {
+		init := evalStatement(us.Init, env)
+		if init.Type() == object.OBJECT_ERR {
+			return init
+		}
+	}
+	for {
+		if us.Condition != nil {
+			cond := evalExpression(us.Condition, env)
+			if cond.Type() == object.OBJECT_ERR {
+				return cond
+			}
+			if !condIsTrue(cond) {
+				break
+			}
+		}
+		eval, stop := evalLoopBody(us.Body, env)
+		if stop {
+			return eval
+		}
+		if us.Update != nil {
+			update := evalStatement(us.Update, env)
+			if update.Type() == object.OBJECT_ERR {
+				return update
+			}
+		}
+	}
+	return &object.Nil{}
+}
+
+func evalUntukDalamStatement(ud *ast.UntukDalamStatement, env *object.Environment) object.Object {
+	iter := evalExpression(ud.Iterable, env)
+	if k, ok := iter.(*object.Kembalikan); ok {
+		iter = k.Value
+	}
+	var el []object.Object
+	switch it := iter.(type) {
+	case *object.Error:
+		return it
+	case *object.Array:
+		el = it.El
+	case *object.String:
+		for _, r := range it.Value {
+			el = append(el, &object.String{Value: string(r), Ln: ud.Ln})
+		}
+	case *object.Kamus:
+		for _, hk := range it.Keys { // iterate the keys in insertion order
+			el = append(el, it.Pairs[hk].Key)
+		}
+	default:
+		return newTypeError("untuk dalam hanya menerima string, array atau kamus", iter.Inspect(), ud.Ln)
+	}
+	for _, e := range el {
+		env.Set(ud.Ident.Value, e)
+		if eval, stop := evalLoopBody(ud.Body, env); stop {
+			return eval
+		}
+	}
+	return &object.Nil{}
+}
+
+// evalLoopBody run one iteration of the loop body. stop is true when the loop must end, in which case eval is what the loop statement should return
+func evalLoopBody(body *ast.BlockStatement, env *object.Environment) (eval object.Object, stop bool) {
+	eval = evalBlockStatement(body, env)
+	switch eval.(type) {
+	case *object.Kembalikan, *object.Error:
+		return eval, true // unwind to the caller
+	case *object.Henti:
+		return &object.Nil{}, true
+	}
+	return eval, false
+}
+
 func evalFungsiLiteral(fl *ast.FungsiExpression, env *object.Environment) object.Object {
 	return &object.FungsiLiteral{Param: fl.Params, Body: fl.Body, Env: env, Ln: fl.Line()}
 }
@@ -226,22 +556,54 @@ func runFunction(fn object.Object, e *ast.CallExpression, env *object.Environmen
 	if len(args) == 1 && args[0].Type() == object.OBJECT_ERR { // if there's error
 		return args[0]
 	}
+	return applyFunction(fn, args, e.Line(), env)
+}
+
+const maxCallDepth = 10000
+
+// callDepth is how deep the function call currently is. the evaluator run one program at a time, so it's kept here instead of in the Environment
+var callDepth int
+
+// applyFunction call fn with the already evaluated args. it's also used by builtins to call back into kusmala function (e.g peta)
+func applyFunction(fn object.Object, args []object.Object, l int, env *object.Environment) object.Object {
+	if b, ok := fn.(*object.Builtin); ok {
+		return b.Fn(env, l, args...)
+	}
 	f, ok := fn.(*object.FungsiLiteral)
 	if !ok {
-		return newError("bukan sebuah fungsi", fn.Inspect(), fn.Line())
+		return newCodedError(diagnostic.BUKAN_FUNGSI, "bukan sebuah fungsi", fn.Inspect(), l)
 	}
 	if len(args) != len(f.Param) {
 		s := fmt.Sprintf("fungsi membutuhkan %d parameter namun menemukan %d argumen", len(f.Param), len(args))
-		return newError(s, e.TokenLiteral(), e.Line())
+		return newArityError(s, fungsiName(f), l)
 	}
 	childEnv := extendFuncEnv(f, args)
+	if callDepth >= maxCallDepth { // stop it before Go stack overflow, which can't be recovered and kill the REPL
+		return newCodedError(diagnostic.REKURSI_TERLALU_DALAM, fmt.Sprintf("pemanggilan fungsi melebihi %d tingkat", maxCallDepth), fungsiName(f), l)
+	}
+	callDepth++
+	defer func() { callDepth-- }() // also when it unwind by panic, e.g keluar
 	eval := evalStatement(f.Body, childEnv)
-	if v, ok := eval.(*object.Kembalikan); ok {
+	switch v := eval.(type) {
+	case *object.Kembalikan:
 		eval = v.Value
+	case *object.Henti, *object.Lanjut:
+		eval = outsideLoopError(v)
+	}
+	if err, ok := eval.(*object.Error); ok {
+		// the error unwind through this call, record it for the stack trace
+		err.Stack = append(err.Stack, object.Frame{Fungsi: fungsiName(f), CallLn: l})
 	}
 	return eval
 }
 
+func fungsiName(f *object.FungsiLiteral) string {
+	if f.Name == "" {
+		return "anonim"
+	}
+	return f.Name
+}
+
 func extendFuncEnv(f *object.FungsiLiteral, args []object.Object) *object.Environment {
 	env := object.NewChildEnv(f.Env)
 	for i, p := range f.Param {
@@ -256,12 +618,12 @@ func evalBlockStatement(bs *ast.BlockStatement, env *object.Environment) object.
 	var obj object.Object
 	for _, s := range bs.Statements {
 		obj = evalStatement(s, env)
-		if v, ok := obj.(*object.Kembalikan); ok {
+		switch v := obj.(type) {
+		case *object.Kembalikan, *object.Henti, *object.Lanjut:
 			return v
 		}
-		if err, ok := obj.(*object.Error); ok {
-			fmt.Println("\t", err.Inspect())
-			continue // so that all error from the blocks from parent to all its child is outputted. change to break to negate
+		if obj.Type() == object.OBJECT_ERR {
+			return obj // stop at the first error
 		}
 	}
 	// only return the last statement from the block
@@ -270,23 +632,28 @@ func evalBlockStatement(bs *ast.BlockStatement, env *object.Environment) object.
 
 func evalKembalikanStatement(ks *ast.KembalikanStatement, env *object.Environment) object.Object {
 	if ks.Expression != nil {
-		return &object.Kembalikan{Value: evalExpression(ks.Expression, env), Ln: ks.Line()}
+		val := evalExpression(ks.Expression, env)
+		if val.Type() == object.OBJECT_ERR {
+			return val
+		}
+		return &object.Kembalikan{Value: val, Ln: ks.Line()}
 	}
 	return &object.Kembalikan{Value: &object.Nil{}, Ln: ks.Line()}
 }
 
 func evalCetakStatement(cs *ast.CetakStatement, env *object.Environment) object.Object {
 	var obj object.Object
+	out := env.Context().Stdout
 	for _, e := range cs.Expression {
 		obj = evalExpression(e, env)
 		if obj.Type() == object.OBJECT_ERR {
 			return obj
 		}
 
-		// cetak statement is just calling Go fmt.Println
-		fmt.Print(obj.Inspect() + " ")
+		// cetak statement is just calling Go fmt.Fprint
+		fmt.Fprint(out, obj.Inspect()+" ")
 	}
-	fmt.Print("\n")
+	fmt.Fprint(out, "\n")
 	// only return the last expression
 	return obj
 }
@@ -296,56 +663,82 @@ func evalReassignStatement(rs *ast.ReassignStatement, env *object.Environment, l
 	if expr.Type() == object.OBJECT_ERR {
 		return expr
 	}
-	_, ok := env.Get(rs.Ident.Value)
-	if !ok {
-		return newError("pengenal tidak diketahui", rs.Ident.TokenLiteral(), l)
+	if !env.Assign(rs.Ident.Value, expr) {
+		return newCodedError(diagnostic.PENGENAL_TIDAK_DIKENAL, "pengenal tidak diketahui", rs.Ident.TokenLiteral(), l)
 	}
-	traverseEnv(rs.Ident.Value, env, expr)
-	env.Set(rs.Ident.Value, expr)
 	return &object.Nil{}
 }
 
-// TODO: find out more about this
-func traverseEnv(name string, env *object.Environment, expr object.Object) {
-	if env == nil {
-		return
+// arr[i] = x mutate the array in place, so every variable that refer to the same array see the change
+func evalIndexAssignStatement(ia *ast.IndexAssignStatement, env *object.Environment) object.Object {
+	left := evalExpression(ia.Target.Left, env)
+	if left.Type() == object.OBJECT_ERR {
+		return left
 	}
-	_, ok := env.Get(name) // we traverse the env and change every value we found
-	if ok {
-		env.Set(name, expr)
-		if env.Master != nil {
-			if _, ok := env.Master.Get(name); !ok {
-				return
-			}
-
+	left = evalLeftIndex(left, ia.Ln)
+	if left.Type() == object.OBJECT_ERR {
+		return left
+	}
+	index := evalExpression(ia.Target.Index, env)
+	if index.Type() == object.OBJECT_ERR {
+		return index
+	}
+	val := evalExpression(ia.NewValue, env)
+	if val.Type() == object.OBJECT_ERR {
+		return val
+	}
+	if kamus, ok := left.(*object.Kamus); ok {
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newTypeError("kunci kamus harus string, integer atau boolean", fmt.Sprintf("[%s]", index.Inspect()), ia.Ln)
 		}
+		kamus.Set(key, val)
+		return &object.Nil{}
 	}
-	traverseEnv(name, env.Master, expr)
-}
-
-func evalPanjangFungsi(e *ast.PanjangFungsi, l int, env *object.Environment) object.Object {
-	arg := evalExpression(e.Argument, env)
-	if arg.Type() != object.OBJECT_STRING && arg.Type() != object.OBJECT_ARRAY {
-		return newError("argumen panjang hanya menerima string atau array", arg.Inspect(), l)
+	arr, ok := left.(*object.Array)
+	if !ok {
+		return newError("teks tidak dapat diubah", left.Inspect(), ia.Ln)
 	}
-	var val int
-	switch a := arg.(type) {
-	case *object.Array:
-		val = len(a.El)
-	case *object.String:
-		val = len(a.Value)
+	i, err := arrayIndex(arr, index, ia.Ln)
+	if err != nil {
+		return err
 	}
-	return &object.Integer{Ln: l, Value: val}
+	arr.El[i] = val
+	return &object.Nil{}
 }
 
 func evalArray(a *ast.ArrayLiteral, l int, env *object.Environment) object.Object {
 	arr := &object.Array{Ln: a.Ln}
 	for _, v := range a.Elements {
-		arr.El = append(arr.El, evalExpression(v, env))
+		el := evalExpression(v, env)
+		if el.Type() == object.OBJECT_ERR {
+			return el
+		}
+		arr.El = append(arr.El, el)
 	}
 	return arr
 }
 
+func evalKamus(k *ast.KamusLiteral, env *object.Environment) object.Object {
+	kamus := object.NewKamus(k.Ln)
+	for i, ke := range k.Keys {
+		key := evalExpression(ke, env)
+		if key.Type() == object.OBJECT_ERR {
+			return key
+		}
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return newTypeError("kunci kamus harus string, integer atau boolean", key.Inspect(), k.Ln)
+		}
+		val := evalExpression(k.Values[i], env)
+		if val.Type() == object.OBJECT_ERR {
+			return val
+		}
+		kamus.Set(hashable, val)
+	}
+	return kamus
+}
+
 func evalIndexExpression(left object.Object, index object.Object, l int, env *object.Environment) object.Object {
 	le := evalLeftIndex(left, l)
 	if le.Type() == object.OBJECT_ERR {
@@ -359,33 +752,130 @@ func evalIndexExpression(left object.Object, index object.Object, l int, env *ob
 }
 
 func evalLeftIndex(left object.Object, l int) object.Object {
+	if k, ok := left.(*object.Kembalikan); ok {
+		left = k.Value
+	}
 	switch t := left.(type) {
-	case *object.Kembalikan:
-		switch k := t.Value.(type) {
-		case *object.Array:
-			return k
-		default:
-			return newError("struktur data tidak didukung operator index", k.Inspect(), l)
-		}
-	case *object.Array:
+	case *object.Array, *object.Kamus, *object.String:
 		return t
 	default:
-		return newError("struktur data tidak didukung operator index", left.Inspect(), l)
+		return newTypeError("struktur data tidak didukung operator index", left.Inspect(), l)
 	}
 }
 
 func evalIndex(le object.Object, index object.Object, l int) object.Object {
+	switch t := le.(type) {
+	case *object.Kamus:
+		return evalKamusIndex(t, index, l)
+	case *object.String:
+		// index by character (rune), not by byte, so "é"[0] is "é"
+		runes := []rune(t.Value)
+		i, err := checkIndex(index, len(runes), "teks", l)
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: string(runes[i]), Ln: l}
+	}
+	arr := le.(*object.Array)
+	i, err := arrayIndex(arr, index, l)
+	if err != nil {
+		return err
+	}
+	return arr.El[i]
+}
+
+// arrayIndex check that index is an integer within the bound of arr
+func arrayIndex(arr *object.Array, index object.Object, l int) (int, *object.Error) {
+	return checkIndex(index, len(arr.El), "array", l)
+}
+
+// checkIndex check that index is an integer within the length. jenis is the name of the data structure for the error message
+func checkIndex(index object.Object, length int, jenis string, l int) (int, *object.Error) {
 	i, ok := index.(*object.Integer)
 	if !ok {
-		return newError("argumen index harus sebuah integer", fmt.Sprintf("[%s]", index.Inspect()), l)
+		return 0, newTypeError("argumen index harus sebuah integer", fmt.Sprintf("[%s]", index.Inspect()), l)
 	}
-	arr := le.(*object.Array)
 	if i.Value < 0 {
-		return newError("argumen index tidak boleh negatif", fmt.Sprintf("[%s]", i.Inspect()), l)
-	} else if i.Value > len(arr.El)-1 {
-		return newError("argumen index melebihi panjang array", fmt.Sprintf("[%s]", i.Inspect()), l)
+		return 0, newIndexError("argumen index tidak boleh negatif", fmt.Sprintf("[%s]", i.Inspect()), l)
+	} else if i.Value > length-1 {
+		return 0, newIndexError("argumen index melebihi panjang "+jenis, fmt.Sprintf("[%s]", i.Inspect()), l)
 	}
-	return arr.El[i.Value]
+	return i.Value, nil
+}
+
+// arr[a:b] return a new array with element a until b-1. omitted a is 0 and omitted b is the length of the array.
+// slicing string work the same way but by character
+func evalSliceExpression(se *ast.SliceExpression, env *object.Environment) object.Object {
+	left := evalExpression(se.Left, env)
+	if left.Type() == object.OBJECT_ERR {
+		return left
+	}
+	if k, ok := left.(*object.Kembalikan); ok {
+		left = k.Value
+	}
+	var length int
+	var jenis string
+	var runes []rune
+	switch t := left.(type) {
+	case *object.Array:
+		length, jenis = len(t.El), "array"
+	case *object.String:
+		runes = []rune(t.Value)
+		length, jenis = len(runes), "teks"
+	default:
+		return newTypeError("struktur data tidak didukung operator slice", left.Inspect(), se.Ln)
+	}
+	start, err := evalSliceBound(se.Start, 0, length, jenis, se.Ln, env)
+	if err != nil {
+		return err
+	}
+	end, err := evalSliceBound(se.End, length, length, jenis, se.Ln, env)
+	if err != nil {
+		return err
+	}
+	if start > end {
+		return newError("awal slice tidak boleh melebihi akhir slice", fmt.Sprintf("[%d:%d]", start, end), se.Ln)
+	}
+	if _, ok := left.(*object.String); ok {
+		return &object.String{Value: string(runes[start:end]), Ln: se.Ln}
+	}
+	el := make([]object.Object, end-start)
+	copy(el, left.(*object.Array).El[start:end])
+	return &object.Array{El: el, Ln: se.Ln}
+}
+
+// unlike index, the bound of slice could be equal to the length
+func evalSliceBound(expr ast.Expression, def int, length int, jenis string, l int, env *object.Environment) (int, object.Object) {
+	if expr == nil {
+		return def, nil
+	}
+	obj := evalExpression(expr, env)
+	if obj.Type() == object.OBJECT_ERR {
+		return 0, obj
+	}
+	i, ok := obj.(*object.Integer)
+	if !ok {
+		return 0, newTypeError("argumen slice harus sebuah integer", fmt.Sprintf("[%s]", obj.Inspect()), l)
+	}
+	if i.Value < 0 {
+		return 0, newIndexError("argumen slice tidak boleh negatif", fmt.Sprintf("[%s]", i.Inspect()), l)
+	} else if i.Value > length {
+		return 0, newIndexError("argumen slice melebihi panjang "+jenis, fmt.Sprintf("[%s]", i.Inspect()), l)
+	}
+	return i.Value, nil
+}
+
+// looking up a key that doesn't exist return NIL, so it could be checked with jika
+func evalKamusIndex(kamus *object.Kamus, index object.Object, l int) object.Object {
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return newTypeError("kunci kamus harus string, integer atau boolean", fmt.Sprintf("[%s]", index.Inspect()), l)
+	}
+	val, ok := kamus.Get(key)
+	if !ok {
+		return &object.Nil{}
+	}
+	return val
 }
 
 func condIsTrue(cond object.Object) bool {
@@ -401,5 +891,33 @@ func condIsTrue(cond object.Object) bool {
 }
 
 func newError(msg string, a any, l int) *object.Error {
-	return &object.Error{Msg: fmt.Sprintf("%d: %s dekat '%v'", l, msg, a)}
+	return newCodedError(diagnostic.KESALAHAN_RUNTIME, msg, a, l)
+}
+
+// newCodedError is newError with a specific error code, see the diagnostic package. the hint come from the code
+func newCodedError(code string, msg string, a any, l int) *object.Error {
+	return &object.Error{Msg: fmt.Sprintf("%s dekat '%v'", msg, a), Ln: l, Code: code, Hint: errorHints[code]}
+}
+
+// the operand or argument has the wrong type
+func newTypeError(msg string, a any, l int) *object.Error {
+	return newCodedError(diagnostic.KESALAHAN_TIPE, msg, a, l)
+}
+
+// the index or slice bound is outside the string or array
+func newIndexError(msg string, a any, l int) *object.Error {
+	return newCodedError(diagnostic.INDEX_DI_LUAR_BATAS, msg, a, l)
+}
+
+// the function is called with the wrong number of arguments
+func newArityError(msg string, a any, l int) *object.Error {
+	return newCodedError(diagnostic.JUMLAH_ARGUMEN, msg, a, l)
+}
+
+var errorHints = map[string]string{
+	diagnostic.PENGENAL_TIDAK_DIKENAL: "buat variabel terlebih dahulu dengan 'buat', atau periksa penulisan namanya",
+	diagnostic.PEMBAGIAN_NOL:          "periksa pembaginya sebelum membagi, contoh: jika (b != 0) { ... }",
+	diagnostic.INDEX_DI_LUAR_BATAS:    "index dimulai dari 0 sampai panjang(x) - 1",
+	diagnostic.KESALAHAN_INTERNAL:     "ini adalah bug di kusmala, mohon laporkan",
+	diagnostic.REKURSI_TERLALU_DALAM:  "pastikan fungsi rekursif memiliki kondisi berhenti",
 }
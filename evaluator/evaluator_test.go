@@ -1,8 +1,12 @@
 package evaluator
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/vricap/kusmala/ast"
+	"github.com/vricap/kusmala/diagnostic"
 	"github.com/vricap/kusmala/lexer"
 	"github.com/vricap/kusmala/object"
 	"github.com/vricap/kusmala/parser"
@@ -14,9 +18,20 @@ func testVal(in string) object.Object {
 	tree := pars.ConstructTree()
 	env := object.NewEnv()
 
-	// TODO: this fuck my test case
-	eval := Eval(tree, env)
-	return eval[len(eval)-1]
+	res := Eval(tree, env)
+	if res.Err != nil {
+		return res.Err
+	}
+	return res.Values[len(res.Values)-1]
+}
+
+// testOutput run the program with the given stdin and return what it write to stdout
+func testOutput(in string, stdin string) (string, *Result) {
+	var out bytes.Buffer
+	tree := parser.NewPars(lexer.NewLex(in)).ConstructTree()
+	env := object.NewEnvWithContext(object.NewContext(strings.NewReader(stdin), &out, &out))
+	res := Eval(tree, env)
+	return out.String(), res
 }
 
 func TestIntegerExpression(t *testing.T) {
@@ -35,6 +50,24 @@ func TestIntegerExpression(t *testing.T) {
 		{"20 + 2 * -10", 0},
 		{"50 / 2 * 2 + 10", 60},
 		{"3 * 3 * 3 + 10", 37},
+		{"(1 + 2) * 3", 9},
+		{"2 * (5 + 10)", 30},
+		{"-(5 + 5)", -10},
+		{"(5 + 10 * 2 + 15 / 3) * 2 + -10", 50},
+		{"((2))", 2},
+		{"7 % 3", 1},
+		{"-7 % 3", -1},
+		{"10 % 2", 0},
+		{"7 div 2", 3},
+		{"-7 div 2", -3},
+		{"(7 div 2) * 2 + 7 % 2", 7},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"(2 ** 3) ** 2", 64},
+		{"-2 ** 2", -4},
+		{"(-2) ** 3", -8},
+		{"5 ** 0", 1},
+		{"2 * 3 ** 2", 18},
 	}
 
 	for _, tt := range test {
@@ -43,6 +76,112 @@ func TestIntegerExpression(t *testing.T) {
 	}
 }
 
+func TestFloatExpression(t *testing.T) {
+	test := []struct {
+		in     string
+		expect float64
+	}{
+		{"3.5", 3.5},
+		{"-2.5", -2.5},
+		{"1e3", 1000},
+		{"1.5 + 1.5", 3},
+		{"1 + 0.5", 1.5},
+		{"0.5 * 4", 2},
+		{"10.0 / 4", 2.5},
+		{"10 / 4.0", 2.5},
+		{"7.5 - 10", -2.5},
+		{"(1 + 0.5) * 2", 3},
+		{"7.5 % 2", 1.5},
+		{"7.5 div 2", 3},
+		{"2 ** 0.5 ** 2", 1.189207115002721},
+		{"2 ** -1", 0.5},
+		{"4.0 ** 2", 16},
+	}
+
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		testFloatObject(t, eval, tt.expect)
+	}
+}
+
+func TestDivisionType(t *testing.T) {
+	test := []struct {
+		in     string
+		expect object.ObjectType
+	}{
+		{"10 / 2", object.OBJECT_INTEGER},
+		{"10 / 4", object.OBJECT_INTEGER},
+		{"10 div 4", object.OBJECT_INTEGER},
+		{"10.0 / 2", object.OBJECT_FLOAT},
+		{"10 / 4.0", object.OBJECT_FLOAT},
+		{"10.0 div 4", object.OBJECT_FLOAT},
+	}
+
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		if eval.Type() != tt.expect {
+			t.Fatalf("%q: eval.Type() is not %s. got: %s (%s)", tt.in, tt.expect, eval.Type(), eval.Inspect())
+		}
+	}
+	testIntegerObject(t, testVal("10 / 4"), 2)
+	testIntegerObject(t, testVal("-7 / 2"), -3)
+	testIntegerObject(t, testVal("buat n = 5; [1, 2, 3][n / 2];"), 3)
+}
+
+func TestFloatComparison(t *testing.T) {
+	test := []struct {
+		in     string
+		expect bool
+	}{
+		{"1.5 < 2", true},
+		{"2 > 1.5", true},
+		{"1.0 == 1", true},
+		{"0.1 + 0.2 != 0.3", true},
+		{"2.5 > 2.5", false},
+	}
+
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		testBooleanObject(t, eval, tt.expect)
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{"3.14", "3.14"},
+		{"2.0", "2.0"},
+		{"10 / 4.0", "2.5"},
+		{"1 / 3.0", "0.3333333333333333"},
+		{"1e21", "1e+21"},
+		{"-0.5", "-0.5"},
+	}
+
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		if eval.Inspect() != tt.expect {
+			t.Fatalf("eval.Inspect() is not %s. got: %s", tt.expect, eval.Inspect())
+		}
+	}
+}
+
+func TestGanjilGenap(t *testing.T) {
+	in := `
+	buat genap = 0;
+	buat ganjil = 0;
+	untuk (buat i = 1; i <= 9; i = i + 1) {
+		jika (i % 2 == 0) {
+			genap = genap + 1;
+		} lainnya {
+			ganjil = ganjil + 1;
+		}
+	}
+	genap * 10 + ganjil;`
+	testIntegerObject(t, testVal(in), 45)
+}
+
 func TestBoolean(t *testing.T) {
 	test := []struct {
 		in     string
@@ -63,6 +202,15 @@ func TestBoolean(t *testing.T) {
 		{"benar == salah", false},
 		{"benar != salah", true},
 		{"salah != benar", true},
+		{"1 <= 1", true},
+		{"1 <= 0", false},
+		{"2 >= 3", false},
+		{"3 >= 3", true},
+		{"1.5 <= 1.5", true},
+		{"2 >= 2.5", false},
+		{"salah < benar", true},
+		{"benar <= salah", false},
+		{"benar >= benar", true},
 		// {"1 == benar", true},
 		// {"1 != benar", false},
 	}
@@ -73,6 +221,76 @@ func TestBoolean(t *testing.T) {
 	}
 }
 
+func TestStringComparison(t *testing.T) {
+	test := []struct {
+		in     string
+		expect bool
+	}{
+		{`"apel" == "apel"`, true},
+		{`"apel" != "apel"`, false},
+		{`"apel" == "Apel"`, false},
+		{`"apel" < "jeruk"`, true},
+		{`"apel" > "jeruk"`, false},
+		{`"apel" < "apelx"`, true},
+		{`"Zebra" < "apel"`, true}, // uppercase is ordered before lowercase
+		{`"b" >= "a"`, true},
+		{`"a" <= "a"`, true},
+		{`"" < "a"`, true},
+	}
+
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		testBooleanObject(t, eval, tt.expect)
+	}
+}
+
+func TestLogicalOperator(t *testing.T) {
+	test := []struct {
+		in     string
+		expect bool
+	}{
+		{"benar && benar", true},
+		{"benar && salah", false},
+		{"salah || benar", true},
+		{"salah || salah", false},
+		{"benar dan salah", false},
+		{"salah atau benar", true},
+		{"1 < 2 && 2 < 3", true},
+		{"1 > 2 || 2 > 3", false},
+		{"salah || benar && salah", false},
+		{"1 && 0", true}, // integer is truthy
+		{"!(benar && salah)", true},
+	}
+
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		testBooleanObject(t, eval, tt.expect)
+	}
+}
+
+func TestLogicalShortCircuit(t *testing.T) {
+	test := []struct {
+		in     string
+		expect int
+	}{
+		// the right side would be an error if evaluated
+		{"buat x = salah && tidakAda; jika (x) { 1 } lainnya { 2 }", 2},
+		{"buat x = benar || tidakAda; jika (x) { 1 } lainnya { 2 }", 1},
+		{`
+		buat n = 0;
+		buat tambah = fungsi() { n = n + 1; kembalikan benar; };
+		salah dan tambah();
+		benar atau tambah();
+		benar dan tambah();
+		n;`, 1},
+	}
+
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		testIntegerObject(t, eval, tt.expect)
+	}
+}
+
 func TestBangOperator(t *testing.T) {
 	test := []struct {
 		in     string
@@ -104,6 +322,7 @@ func TestJikaStatement(t *testing.T) {
 		{"jika (1 > 2) { 10 }", nil},
 		{"jika (1 > 2) { 10 } lainnya { 20 }", 20},
 		{"jika (1 < 2) { 10 } lainnya { 20 }", 10},
+		{"jika ((1 + 1) * 2 == 4) { 10 } lainnya { 20 }", 10},
 	}
 	for _, tt := range test {
 		eval := testVal(tt.in)
@@ -176,6 +395,15 @@ func TestErrorHandling(t *testing.T) {
 			kembalikan 1;
 		}
 		`, "ERROR di baris 4: operator tidak didukung dekat 'benar - salah'"},
+		{"1 / 0;", "ERROR di baris 1: pembagian dengan nol dekat '1 / 0'"},
+		{"10 % (5 - 5);", "ERROR di baris 1: pembagian dengan nol dekat '10 % 0'"},
+		{"7 div 0;", "ERROR di baris 1: pembagian dengan nol dekat '7 div 0'"},
+		{"1.5 / 0;", "ERROR di baris 1: pembagian dengan nol dekat '1.5 / 0'"},
+		{`
+		buat bagi = fungsi(a, b) {
+			kembalikan a / b;
+		};
+		bagi(1, 0);`, "ERROR di baris 3: pembagian dengan nol dekat '1 / 0'"},
 	}
 	for i, tt := range test {
 		eval := testVal(tt.in)
@@ -189,6 +417,213 @@ func TestErrorHandling(t *testing.T) {
 	}
 }
 
+func TestEvalResult(t *testing.T) {
+	in := `
+buat a = 1;
+a + 1;
+jika (a == 1) {
+	buat b = 2;
+	tidakAda + b;
+	b = 100;
+}
+a = 3;
+`
+	tree := parser.NewPars(lexer.NewLex(in)).ConstructTree()
+	env := object.NewEnv()
+	res := Eval(tree, env)
+
+	if len(res.Values) != 2 {
+		t.Fatalf("len(res.Values) is not 2. got: %d", len(res.Values))
+	}
+	testIntegerObject(t, res.Values[1], 2)
+	if res.Err == nil {
+		t.Fatalf("res.Err is nil")
+	}
+	if res.Err.Ln != 6 {
+		t.Fatalf("res.Err.Ln is not 6. got: %d", res.Err.Ln)
+	}
+	expect := "pengenal tidak diketahui dekat 'tidakAda'"
+	if res.Err.Msg != expect {
+		t.Fatalf("res.Err.Msg is not '%s'. got: %s", expect, res.Err.Msg)
+	}
+	// the evaluation stop at the first error, so the statement after it never run
+	if b, _ := env.Get("b"); b.Inspect() != "2" {
+		t.Fatalf("b is not 2. got: %s", b.Inspect())
+	}
+	if a, _ := env.Get("a"); a.Inspect() != "1" {
+		t.Fatalf("a is not 1. got: %s", a.Inspect())
+	}
+
+	res = Eval(parser.NewPars(lexer.NewLex("1; 2;")).ConstructTree(), object.NewEnv())
+	if res.Err != nil {
+		t.Fatalf("res.Err is not nil. got: %s", res.Err.Inspect())
+	}
+}
+
+func TestErrorPropagation(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{"foo + 1;", "ERROR di baris 1: pengenal tidak diketahui dekat 'foo'"},
+		{"1 + foo;", "ERROR di baris 1: pengenal tidak diketahui dekat 'foo'"},
+		{"-foo;", "ERROR di baris 1: pengenal tidak diketahui dekat 'foo'"},
+		{"[1, foo];", "ERROR di baris 1: pengenal tidak diketahui dekat 'foo'"},
+		{"buat x = foo;", "ERROR di baris 1: pengenal tidak diketahui dekat 'foo'"},
+		{"jika (foo) { 1 }", "ERROR di baris 1: pengenal tidak diketahui dekat 'foo'"},
+		{"buat f = fungsi() { kembalikan 1 / 0; }; f() + 1;", "ERROR di baris 1: pembagian dengan nol dekat '1 / 0'"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		e, ok := eval.(*object.Error)
+		if !ok {
+			t.Fatalf("eval is not *object.Error. got: %T", eval)
+		}
+		if e.Inspect() != tt.expect {
+			t.Fatalf("e.Inspect() is not: '%s'. got: %s", tt.expect, e.Inspect())
+		}
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	in := `buat faktorial = fungsi(x) {
+	jika (x == 0) {
+		kembalikan 1 / 0;
+	}
+	kembalikan x * faktorial(x - 1);
+};
+buat hitung = fungsi() {
+	kembalikan faktorial(2);
+};
+hitung();`
+	eval := testVal(in)
+	e, ok := eval.(*object.Error)
+	if !ok {
+		t.Fatalf("eval is not *object.Error. got: %T", eval)
+	}
+	expect := []string{
+		"di fungsi faktorial (baris 3) dipanggil dari baris 5",
+		"di fungsi faktorial (baris 5) dipanggil dari baris 5",
+		"di fungsi faktorial (baris 5) dipanggil dari baris 8",
+		"di fungsi hitung (baris 8) dipanggil dari baris 10",
+	}
+	trace := e.StackTrace()
+	if len(trace) != len(expect) {
+		t.Fatalf("len(trace) is not %d. got: %d (%v)", len(expect), len(trace), trace)
+	}
+	for i := range expect {
+		if trace[i] != expect[i] {
+			t.Fatalf("trace[%d] is not '%s'. got: %s", i, expect[i], trace[i])
+		}
+	}
+}
+
+func TestStackTraceAnonymousAndArguments(t *testing.T) {
+	// the error happen while evaluating the argument, so it's not inside f
+	eval := testVal("buat f = fungsi(x) { x; }; f(1 / 0);")
+	if e, ok := eval.(*object.Error); !ok || len(e.StackTrace()) != 0 {
+		t.Fatalf("eval is not *object.Error without stack trace. got: %s", eval.Inspect())
+	}
+
+	eval = testVal("fungsi() { -benar; }();")
+	e, ok := eval.(*object.Error)
+	if !ok {
+		t.Fatalf("eval is not *object.Error. got: %T", eval)
+	}
+	expect := "di fungsi anonim (baris 1) dipanggil dari baris 1"
+	if trace := e.StackTrace(); len(trace) != 1 || trace[0] != expect {
+		t.Fatalf("e.StackTrace() is not ['%s']. got: %v", expect, trace)
+	}
+}
+
+func TestStackTraceDeepRecursion(t *testing.T) {
+	in := `buat turun = fungsi(n) {
+	jika (n == 0) {
+		kembalikan tidakAda;
+	}
+	kembalikan turun(n - 1);
+};
+turun(50);`
+	eval := testVal(in)
+	e, ok := eval.(*object.Error)
+	if !ok {
+		t.Fatalf("eval is not *object.Error. got: %T", eval)
+	}
+	if len(e.Stack) != 51 {
+		t.Fatalf("len(e.Stack) is not 51. got: %d", len(e.Stack))
+	}
+	trace := e.StackTrace()
+	if len(trace) != 11 {
+		t.Fatalf("len(trace) is not 11. got: %d", len(trace))
+	}
+	if trace[5] != "... 41 pemanggilan lainnya ..." {
+		t.Fatalf("trace[5] is not the elision line. got: %s", trace[5])
+	}
+	if trace[10] != "di fungsi turun (baris 5) dipanggil dari baris 7" {
+		t.Fatalf("the last frame is not the outermost call. got: %s", trace[10])
+	}
+}
+
+func TestRecursionLimit(t *testing.T) {
+	env := object.NewEnv()
+	tree := parser.NewPars(lexer.NewLex("buat f = fungsi(n) { kembalikan f(n + 1); }; f(0);")).ConstructTree()
+	res := Eval(tree, env)
+	if res.Err == nil || res.Err.Code != diagnostic.REKURSI_TERLALU_DALAM {
+		t.Fatalf("expected recursion error. got: %v", res.Err)
+	}
+	if callDepth != 0 {
+		t.Fatalf("the call depth is not restored. got: %d", callDepth)
+	}
+	// the session is still usable and a deep but finite recursion still work
+	tree = parser.NewPars(lexer.NewLex("buat g = fungsi(n) { jika (n == 0) { kembalikan 0; } kembalikan 1 + g(n - 1); }; g(5000);")).ConstructTree()
+	res = Eval(tree, env)
+	if res.Err != nil {
+		t.Fatalf("unexpected error: %s", res.Err.Msg)
+	}
+	testIntegerObject(t, res.Values[1], 5000)
+}
+
+func TestCetakOutput(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{`cetak("Halo", "Dunia");`, "Halo Dunia \n"},
+		{`cetak(1 + 2);`, "3 \n"},
+		{`untuk (x dalam [1, 2]) { cetak(x); }`, "1 \n2 \n"},
+		{`buat f = fungsi(x) { cetak(x); }; f([1, 2]);`, "[1, 2] \n"},
+		{`cetak(1); cetak(tidakAda); cetak(2);`, "1 \n"},
+		{`cetak("a\tb\n\"c\"");`, "a\tb\n\"c\" \n"},
+		{"cetak(`baris\n\\n`);", "baris\n\\n \n"},
+	}
+	for _, tt := range test {
+		out, _ := testOutput(tt.in, "")
+		if out != tt.expect {
+			t.Fatalf("output is not %q. got: %q", tt.expect, out)
+		}
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	// a statement with nil expression is never produced by a valid program, evaluating it make Go panic
+	tree := &ast.Tree{Statements: []ast.Statement{
+		&ast.BuatStatement{Name: &ast.Identifier{Value: "a"}, Expression: &ast.IntegerLiteral{Value: 1}, Ln: 1},
+		&ast.ExpressionStatement{Ln: 2},
+		&ast.ExpressionStatement{Expression: &ast.IntegerLiteral{Value: 3}, Ln: 3},
+	}}
+	res := Eval(tree, object.NewEnv())
+	if len(res.Values) != 1 {
+		t.Fatalf("len(res.Values) is not 1. got: %d", len(res.Values))
+	}
+	if res.Err == nil {
+		t.Fatalf("res.Err is nil")
+	}
+	expect := "ERROR di baris 2: kesalahan internal interpreter"
+	if !strings.HasPrefix(res.Err.Inspect(), expect) {
+		t.Fatalf("res.Err.Inspect() does not start with '%s'. got: %s", expect, res.Err.Inspect())
+	}
+}
+
 func TestBuatStatement(t *testing.T) {
 	test := []struct {
 		in     string
@@ -258,6 +693,315 @@ addTwo(2);`
 	testIntegerObject(t, testVal(input), 4)
 }
 
+func TestNestedClosures(t *testing.T) {
+	test := []struct {
+		in     string
+		expect int
+	}{
+		// a closure three function deep could see the top-level variable
+		{`
+		buat a = 1;
+		buat f = fungsi() {
+			buat b = 2;
+			fungsi() {
+				buat c = 3;
+				fungsi() { a + b + c; };
+			};
+		};
+		f()()();`, 6},
+		{`
+		buat tambah = fungsi(x) { fungsi(y) { fungsi(z) { x + y + z; }; }; };
+		tambah(1)(2)(3);`, 6},
+		// counter keep its own state
+		{`
+		buat buatPenghitung = fungsi() {
+			buat n = 0;
+			fungsi() { n = n + 1; kembalikan n; };
+		};
+		buat hitungA = buatPenghitung();
+		buat hitungB = buatPenghitung();
+		hitungA(); hitungA(); hitungB();
+		hitungA() * 10 + hitungB();`, 32},
+		// the closure see the latest value of the captured variable
+		{`
+		buat x = 1;
+		buat ambil = fungsi() { x; };
+		x = 5;
+		ambil();`, 5},
+	}
+	for _, tt := range test {
+		testIntegerObject(t, testVal(tt.in), tt.expect)
+	}
+}
+
+func TestShadowing(t *testing.T) {
+	test := []struct {
+		in     string
+		expect int
+	}{
+		// buat inside function shadow the outer variable without changing it
+		{"buat x = 1; buat f = fungsi() { buat x = 2; x; }; f(); x;", 1},
+		{"buat x = 1; buat f = fungsi() { buat x = 2; x; }; f();", 2},
+		// parameter shadow the outer variable
+		{"buat x = 1; buat f = fungsi(x) { x = x + 10; x; }; f(5) + x;", 16},
+		// reassign change the nearest binding only
+		{`
+		buat x = 1;
+		buat f = fungsi() {
+			buat x = 2;
+			buat g = fungsi() { x = 20; };
+			g();
+			kembalikan x;
+		};
+		f() + x;`, 21},
+		// reassign without shadowing change the outer variable
+		{"buat x = 1; buat f = fungsi() { x = 7; }; f(); x;", 7},
+		// block doesn't create new scope
+		{"jika (benar) { buat y = 3; } y;", 3},
+		// buat in the same scope replace the old one
+		{"buat x = 1; buat x = x + 1; x;", 2},
+	}
+	for _, tt := range test {
+		testIntegerObject(t, testVal(tt.in), tt.expect)
+	}
+}
+
+func TestReassignUnknown(t *testing.T) {
+	eval := testVal("buat f = fungsi() { z = 1; }; f();")
+	e, ok := eval.(*object.Error)
+	if !ok {
+		t.Fatalf("eval is not *object.Error. got: %T", eval)
+	}
+	expect := "ERROR di baris 1: pengenal tidak diketahui dekat 'z'"
+	if e.Inspect() != expect {
+		t.Fatalf("e.Inspect() is not: '%s'. got: %s", expect, e.Inspect())
+	}
+}
+
+func TestSelamaStatement(t *testing.T) {
+	test := []struct {
+		in     string
+		expect any
+	}{
+		{"buat i = 0; selama (i < 10) { i = i + 1; } i;", 10},
+		{"buat i = 0; buat n = 0; selama (i < 5) { n = n + i; i = i + 1; } n;", 10},
+		{"selama (salah) { 1; }", nil},
+		{"buat i = 0; selama (i > 0) { i = i + 1; } i;", 0},
+		{`
+		buat cari = fungsi(batas) {
+			buat i = 0;
+			selama (benar) {
+				jika (i == batas) {
+					kembalikan i;
+				}
+				i = i + 1;
+			}
+		};
+		cari(7);`, 7},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		int, ok := tt.expect.(int)
+		if ok {
+			testIntegerObject(t, eval, int)
+		} else {
+			testNilObject(t, eval)
+		}
+	}
+}
+
+func TestUntukStatement(t *testing.T) {
+	test := []struct {
+		in     string
+		expect any
+	}{
+		{"buat n = 0; untuk (buat i = 0; i < 5; i = i + 1) { n = n + i; } n;", 10},
+		{"buat i = 0; untuk (; i < 3;) { i = i + 1; } i;", 3},
+		{"buat n = 0; untuk (x dalam [1, 2, 3, 4]) { n = n + x; } n;", 10},
+		{`buat s = ""; untuk (c dalam "abc") { s = c + s; } s;`, "cba"},
+		{"untuk (x dalam []) { x; }", nil},
+		{`
+		buat cari = fungsi(arr, target) {
+			buat i = 0;
+			untuk (x dalam arr) {
+				jika (x == target) {
+					kembalikan i;
+				}
+				i = i + 1;
+			}
+			kembalikan -1;
+		};
+		cari([5, 6, 7], 7);`, 2},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		switch expect := tt.expect.(type) {
+		case int:
+			testIntegerObject(t, eval, expect)
+		case string:
+			testStringObject(t, eval, expect)
+		default:
+			testNilObject(t, eval)
+		}
+	}
+}
+
+func TestHentiLanjut(t *testing.T) {
+	test := []struct {
+		in     string
+		expect int
+	}{
+		{"buat i = 0; selama (benar) { jika (i == 5) { henti; } i = i + 1; } i;", 5},
+		{"buat n = 0; untuk (buat i = 0; i < 10; i = i + 1) { jika (i > 2) { henti; } n = n + 1; } n;", 3},
+		{"buat n = 0; untuk (buat i = 0; i < 5; i = i + 1) { jika (i == 2) { lanjut; } n = n + i; } n;", 8},
+		{"buat n = 0; untuk (x dalam [1, 2, 3, 4]) { jika (x == 3) { lanjut; } n = n + x; } n;", 7},
+		{`
+		buat n = 0;
+		untuk (x dalam [1, 2, 3]) {
+			untuk (y dalam [1, 2, 3]) {
+				jika (y == 2) {
+					henti;
+				}
+				n = n + 1;
+			}
+		}
+		n;`, 3},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		testIntegerObject(t, eval, tt.expect)
+	}
+}
+
+func TestHentiOutsideLoop(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{"henti;", "ERROR di baris 1: statement hanya boleh berada di dalam perulangan dekat 'henti'"},
+		{"buat f = fungsi() { lanjut; }; f();", "ERROR di baris 1: statement hanya boleh berada di dalam perulangan dekat 'lanjut'"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		e, ok := eval.(*object.Error)
+		if !ok {
+			t.Fatalf("eval is not *object.Error. got: %T", eval)
+		}
+		if e.Inspect() != tt.expect {
+			t.Fatalf("e.Inspect() is not: '%s'. got: %s", tt.expect, e.Inspect())
+		}
+	}
+}
+
+func TestKamus(t *testing.T) {
+	test := []struct {
+		in     string
+		expect any
+	}{
+		{`buat k = {"satu": 1, "dua": 2}; k["dua"];`, 2},
+		{`{1: "a", 2: "b"}[1];`, "a"},
+		{`{benar: 1, salah: 0}[1 > 2];`, 0},
+		{`buat kunci = "x"; {"x" + "": 5}[kunci];`, 5},
+		{`{"a": 1}["b"];`, nil},
+		{`panjang({"a": 1, "b": 2, "a": 3});`, 2},
+		{`panjang({"1": 1, 1: 2, "": 3, " ": 4});`, 4},
+		{`buat f = fungsi() { kembalikan {"a": [1, 2, 3]}; }; f()["a"][2];`, 3},
+		{`buat n = 0; untuk (k dalam {"a": 1, "b": 2, "c": 3}) { n = n + {"a": 1, "b": 2, "c": 3}[k]; } n;`, 6},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		switch expect := tt.expect.(type) {
+		case int:
+			testIntegerObject(t, eval, expect)
+		case string:
+			testStringObject(t, eval, expect)
+		default:
+			testNilObject(t, eval)
+		}
+	}
+}
+
+func TestKamusInspect(t *testing.T) {
+	in := `{"b": 1, "a": [1, 2], 3: benar, "b": 2, salah: {"x": "y"}};`
+	expect := "{b: 2, a: [1, 2], 3: benar, salah: {x: y}}"
+	for i := 0; i < 10; i++ { // go map iteration is random, make sure the order doesn't depend on it
+		eval := testVal(in)
+		if eval.Inspect() != expect {
+			t.Fatalf("eval.Inspect() is not %s. got: %s", expect, eval.Inspect())
+		}
+	}
+}
+
+func TestKamusError(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{`{[1]: 2};`, "ERROR di baris 1: kunci kamus harus string, integer atau boolean dekat '[1]'"},
+		{`{"a": 1}[[1]];`, "ERROR di baris 1: kunci kamus harus string, integer atau boolean dekat '[[1]]'"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		e, ok := eval.(*object.Error)
+		if !ok {
+			t.Fatalf("eval is not *object.Error. got: %T", eval)
+		}
+		if e.Inspect() != tt.expect {
+			t.Fatalf("e.Inspect() is not: '%s'. got: %s", tt.expect, e.Inspect())
+		}
+	}
+}
+
+func TestIndexAssign(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{`buat a = [1, 2, 3]; a[0] = 10; a;`, "[10, 2, 3]"},
+		{`buat a = [1, 2]; buat b = a; b[1] = 5; a;`, "[1, 5]"},
+		{`buat m = [[1, 2], [3]]; m[0][1] = 9; m;`, "[[1, 9], [3]]"},
+		{`buat k = {"a": 1}; k["b"] = 2; k["a"] = 3; k;`, "{a: 3, b: 2}"},
+		{`buat a = [0, 0]; buat f = fungsi() { a[1] = 1; }; f(); a;`, "[0, 1]"},
+		{`buat a = [1]; a[1] = 2;`, "ERROR di baris 1: argumen index melebihi panjang array dekat '[1]'"},
+		{`buat a = [1]; a[-1] = 2;`, "ERROR di baris 1: argumen index tidak boleh negatif dekat '[-1]'"},
+		{`buat a = [1]; a["x"] = 2;`, "ERROR di baris 1: argumen index harus sebuah integer dekat '[x]'"},
+		{`buat a = 1; a[0] = 2;`, "ERROR di baris 1: struktur data tidak didukung operator index dekat '1'"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		if eval.Inspect() != tt.expect {
+			t.Fatalf("eval.Inspect() is not: '%s'. got: %s", tt.expect, eval.Inspect())
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{`[1, 2, 3, 4][1:3]`, "[2, 3]"},
+		{`[1, 2, 3, 4][:2]`, "[1, 2]"},
+		{`[1, 2, 3, 4][2:]`, "[3, 4]"},
+		{`[1, 2, 3, 4][:]`, "[1, 2, 3, 4]"},
+		{`[1, 2][1:1]`, "[]"},
+		{`[1, 2][0:2]`, "[1, 2]"},
+		// slice is a copy
+		{`buat a = [1, 2, 3]; buat b = a[0:2]; b[0] = 9; a;`, "[1, 2, 3]"},
+		{`[1, 2][0:3]`, "ERROR di baris 1: argumen slice melebihi panjang array dekat '[3]'"},
+		{`[1, 2][-1:]`, "ERROR di baris 1: argumen slice tidak boleh negatif dekat '[-1]'"},
+		{`[1, 2][2:1]`, "ERROR di baris 1: awal slice tidak boleh melebihi akhir slice dekat '[2:1]'"},
+		{`[1, 2]["a":]`, "ERROR di baris 1: argumen slice harus sebuah integer dekat '[a]'"},
+		{`{"a": 1}[0:1]`, "ERROR di baris 1: struktur data tidak didukung operator slice dekat '{a: 1}'"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		if eval.Inspect() != tt.expect {
+			t.Fatalf("eval.Inspect() is not: '%s'. got: %s", tt.expect, eval.Inspect())
+		}
+	}
+}
+
 func testIntegerObject(t *testing.T, eval object.Object, expect int) {
 	i, ok := eval.(*object.Integer)
 	if !ok {
@@ -268,6 +1012,16 @@ func testIntegerObject(t *testing.T, eval object.Object, expect int) {
 	}
 }
 
+func testFloatObject(t *testing.T, eval object.Object, expect float64) {
+	f, ok := eval.(*object.Float)
+	if !ok {
+		t.Fatalf("object is not *object.Float. got: %T", eval)
+	}
+	if f.Value != expect {
+		t.Fatalf("f.Value is not %g. got: %g", expect, f.Value)
+	}
+}
+
 func testBooleanObject(t *testing.T, eval object.Object, expect bool) {
 	b, ok := eval.(*object.Boolean)
 	if !ok {
@@ -278,9 +1032,124 @@ func testBooleanObject(t *testing.T, eval object.Object, expect bool) {
 	}
 }
 
+func testStringObject(t *testing.T, eval object.Object, expect string) {
+	s, ok := eval.(*object.String)
+	if !ok {
+		t.Fatalf("object is not *object.String. got: %T", eval)
+	}
+	if s.Value != expect {
+		t.Fatalf("s.Value is not %q. got: %q", expect, s.Value)
+	}
+}
+
 func testNilObject(t *testing.T, eval object.Object) {
 	_, ok := eval.(*object.Nil)
 	if !ok {
 		t.Fatalf("eval is not NIL. got %T", eval)
 	}
 }
+
+func TestStringIndex(t *testing.T) {
+	test := []struct {
+		in     string
+		expect string
+	}{
+		{`"kusmala"[0]`, "k"},
+		{`"kusmala"[6]`, "a"},
+		{`"café"[3]`, "é"},
+		{`"halo 👋 dunia"[5]`, "👋"},
+		{`buat s = "selamat pagi"; s[panjang(s) - 1];`, "i"},
+		{`"sate ayam"[5:]`, "ayam"},
+		{`"🍜 bakso 🍜"[2:7]`, "bakso"},
+		{`"ééé"[:2]`, "éé"},
+		{`buat s = ""; untuk(c dalam "naïf 🙂") { s = c + s; } s;`, "🙂 fïan"},
+		{`"abc"[3]`, "ERROR di baris 1: argumen index melebihi panjang teks dekat '[3]'"},
+		{`"é"[1]`, "ERROR di baris 1: argumen index melebihi panjang teks dekat '[1]'"},
+		{`"abc"[-1]`, "ERROR di baris 1: argumen index tidak boleh negatif dekat '[-1]'"},
+		{`"abc"[0:4]`, "ERROR di baris 1: argumen slice melebihi panjang teks dekat '[4]'"},
+		{`buat s = "abc"; s[0] = "x";`, "ERROR di baris 1: teks tidak dapat diubah dekat 'abc'"},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		if eval.Inspect() != tt.expect {
+			t.Fatalf("eval.Inspect() is not: '%s'. got: %s", tt.expect, eval.Inspect())
+		}
+	}
+}
+
+func TestErrorPosition(t *testing.T) {
+	test := []struct {
+		in    string
+		line  int
+		col   int
+		start int // byte offset of the start of the error span
+		end   int
+	}{
+		{"buat a = 1 + tidakAda;", 1, 14, 13, 21},
+		{"buat a = 1;\n  a = a / (a - 1);", 2, 7, 18, 29},
+		{"buat f = fungsi(x) {\n\tx[5];\n};\nf([1]);", 2, 2, 22, 26},
+		{"cetak(1);\npanjang(1, 2);", 2, 1, 10, 23},
+		{"[1, 2][0:\n5];", 1, 1, 0, 12},
+		{"buat f = fungsi() {\n\thenti;\n};\nf();", 2, 2, 21, 27},
+		{"buat x = 1;\nlanjut;", 2, 1, 12, 19},
+	}
+	for _, tt := range test {
+		eval := testVal(tt.in)
+		e, ok := eval.(*object.Error)
+		if !ok {
+			t.Fatalf("eval is not *object.Error. got: %T", eval)
+		}
+		if e.Span.Start.Line != tt.line || e.Span.Start.Col != tt.col {
+			t.Fatalf("%q: the error is not at %d:%d. got: %d:%d", tt.in, tt.line, tt.col, e.Span.Start.Line, e.Span.Start.Col)
+		}
+		if e.Span.Start.Offset != tt.start || e.Span.End.Offset != tt.end {
+			t.Fatalf("%q: e.Span is not [%d:%d]. got: [%d:%d]", tt.in, tt.start, tt.end, e.Span.Start.Offset, e.Span.End.Offset)
+		}
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	test := []struct {
+		in   string
+		code string
+	}{
+		{"tidakAda;", diagnostic.PENGENAL_TIDAK_DIKENAL},
+		{"1 + benar;", diagnostic.KESALAHAN_TIPE},
+		{"tambah(1, 2);", diagnostic.KESALAHAN_TIPE},
+		{"5 / 0;", diagnostic.PEMBAGIAN_NOL},
+		{"[1, 2][2];", diagnostic.INDEX_DI_LUAR_BATAS},
+		{"[1, 2][-1:];", diagnostic.INDEX_DI_LUAR_BATAS},
+		{"buat a = 1; a();", diagnostic.BUKAN_FUNGSI},
+		{"buat f = fungsi(x) { x; }; f();", diagnostic.JUMLAH_ARGUMEN},
+		{"panjang();", diagnostic.JUMLAH_ARGUMEN},
+		{"ulangi(\"a\", -1);", diagnostic.ARGUMEN_TIDAK_VALID},
+		{"ulangi(\"ab\", 9999999999);", diagnostic.ARGUMEN_TIDAK_VALID},
+		{"panjang(1);", diagnostic.KESALAHAN_TIPE},
+		{"{[1]: 2};", diagnostic.KESALAHAN_TIPE},
+		{"hapus([]);", diagnostic.KESALAHAN_RUNTIME},
+		{"masukan(1, 2);", diagnostic.JUMLAH_ARGUMEN},
+	}
+	for _, tt := range test {
+		e, ok := testVal(tt.in).(*object.Error)
+		if !ok {
+			t.Fatalf("%q: eval is not *object.Error", tt.in)
+		}
+		if e.Code != tt.code {
+			t.Fatalf("%q: e.Code is not %s. got: %s (%s)", tt.in, tt.code, e.Code, e.Msg)
+		}
+		if d := e.Diagnostic(); d.Code != tt.code || d.Msg != e.Msg || d.Span != e.Span {
+			t.Fatalf("%q: e.Diagnostic() doesn't match the error. got: %+v", tt.in, d)
+		}
+	}
+}
+
+func TestErrorDiagnosticLine(t *testing.T) {
+	e := newCodedError(diagnostic.KESALAHAN_INTERNAL, "kesalahan internal interpreter", "runtime error", 3)
+	d := e.Diagnostic()
+	if d.Span.Start.Line != 3 || d.Span.Start.Col != 0 {
+		t.Fatalf("error without span must fall back to its line. got: %+v", d.Span)
+	}
+	if !strings.HasPrefix(d.Render(""), "ERROR[R999] di baris 3:") {
+		t.Fatalf("wrong render. got: %q", d.Render(""))
+	}
+}
@@ -1,25 +1,34 @@
 package lexer
 
 import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/vricap/kusmala/token"
 )
 
 type Lexer struct {
-	input   string // the whole code input
-	pos     int    // current position in the input - point to current char
-	peekPos int    // peek the next of the current position
-	char    byte   // current char under examination
-	Line    int
+	input     string // the whole code input
+	pos       int    // current position in the input - point to current char
+	peekPos   int    // peek the next of the current position
+	char      byte   // current char under examination
+	Line      int
+	lineStart int // offset of the first char in the current line, for the column
 }
 
 func NewLex(input string) *Lexer {
-	l := &Lexer{input: input, Line: 1}
+	return NewLexFromLine(input, 1)
+}
+
+// NewLexFromLine is like NewLex but the first line of the input is counted as line. used by the repl so the line number keep counting across inputs
+func NewLexFromLine(input string, line int) *Lexer {
+	l := &Lexer{input: input, Line: line}
 	l.readChar() // so that l.char point to the actual first char in input and not just 0
 	return l
 }
 
 func (lex *Lexer) NextToken() token.Token {
-	var tok token.Token
 	lex.skipWhiteSpace()
 	if lex.isComment() {
 		for lex.isComment() {
@@ -27,7 +36,24 @@ func (lex *Lexer) NextToken() token.Token {
 			lex.skipWhiteSpace()
 		}
 	}
+	start := lex.position(lex.pos)
+	tok := lex.readToken()
+	tok.Start = start
+	tok.End = lex.position(lex.pos)
+	return tok
+}
 
+// position of the offset. the offset must be in the current line
+func (lex *Lexer) position(offset int) token.Position {
+	if offset > len(lex.input) {
+		offset = len(lex.input)
+	}
+	col := utf8.RuneCountInString(lex.input[lex.lineStart:offset]) + 1
+	return token.Position{Offset: offset, Line: lex.Line, Col: col}
+}
+
+func (lex *Lexer) readToken() token.Token {
+	var tok token.Token
 	switch lex.char {
 	case '=':
 		if lex.peekChar() == '=' { // if equal ==
@@ -49,14 +75,50 @@ func (lex *Lexer) NextToken() token.Token {
 		} else {
 			tok = token.NewToken(token.BANG, string(lex.char))
 		}
+	case '&':
+		if lex.peekChar() == '&' {
+			tok = token.NewToken(token.DAN, "&&")
+			lex.pos++
+			lex.peekPos++
+		} else {
+			tok = token.NewToken(token.ILLEGAL, string(lex.char))
+		}
+	case '|':
+		if lex.peekChar() == '|' {
+			tok = token.NewToken(token.ATAU, "||")
+			lex.pos++
+			lex.peekPos++
+		} else {
+			tok = token.NewToken(token.ILLEGAL, string(lex.char))
+		}
 	case '/':
 		tok = token.NewToken(token.SLASH, string(lex.char))
 	case '*':
-		tok = token.NewToken(token.ASTERISK, string(lex.char))
+		if lex.peekChar() == '*' { // exponent **
+			tok = token.NewToken(token.PANGKAT, "**")
+			lex.pos++
+			lex.peekPos++
+		} else {
+			tok = token.NewToken(token.ASTERISK, string(lex.char))
+		}
+	case '%':
+		tok = token.NewToken(token.MODULO, string(lex.char))
 	case '<':
-		tok = token.NewToken(token.LT, string(lex.char))
+		if lex.peekChar() == '=' { // less than or equal <=
+			tok = token.NewToken(token.LT_SAMA, "<=")
+			lex.pos++
+			lex.peekPos++
+		} else {
+			tok = token.NewToken(token.LT, string(lex.char))
+		}
 	case '>':
-		tok = token.NewToken(token.GT, string(lex.char))
+		if lex.peekChar() == '=' { // greater than or equal >=
+			tok = token.NewToken(token.GT_SAMA, ">=")
+			lex.pos++
+			lex.peekPos++
+		} else {
+			tok = token.NewToken(token.GT, string(lex.char))
+		}
 	case '(':
 		tok = token.NewToken(token.LPAREN, string(lex.char))
 	case ')':
@@ -73,8 +135,22 @@ func (lex *Lexer) NextToken() token.Token {
 		tok = token.NewToken(token.COMMA, string(lex.char))
 	case ';':
 		tok = token.NewToken(token.SEMICOLON, string(lex.char))
-	case '"':
-		tok = token.NewToken(token.STRING, lex.readString())
+	case ':':
+		tok = token.NewToken(token.COLON, string(lex.char))
+	case '"', '`':
+		line := lex.Line
+		var str string
+		var ok bool
+		if lex.char == '"' {
+			str, ok = lex.readString()
+		} else {
+			str, ok = lex.readRawString()
+		}
+		if ok {
+			tok = token.NewToken(token.STRING, str)
+		} else {
+			tok = token.NewToken(token.ERROR, fmt.Sprintf("string tidak ditutup di baris %d", line))
+		}
 	case 0:
 		tok = token.NewToken(token.EOF, "")
 	default:
@@ -84,10 +160,8 @@ func (lex *Lexer) NextToken() token.Token {
 			tok = token.NewToken(tokType, tok.Literal)
 			return tok // return early so that readChar at the bottom didn't run again. the pos and peekPos is move up since we already readChar repeatedly inside lex.readIdentifier()
 		} else if isDigit(lex.char) {
-			tok.Literal = lex.readNumber()
-			// fmt.Println(string(lex.char))
-			// fmt.Println(tok.Literal)
-			tok = token.NewToken(token.INTEGER, tok.Literal)
+			lit, tokType := lex.readNumber()
+			tok = token.NewToken(tokType, lit)
 			return tok
 		} else {
 			tok = token.NewToken(token.ILLEGAL, string(lex.char))
@@ -105,12 +179,34 @@ func (lex *Lexer) readIdentifier() string {
 	return lex.input[pos:lex.pos]
 }
 
-func (lex *Lexer) readNumber() string {
+// readNumber read integer (12) or float (3.14, 1e-3, 2.5E10). the returned token type tell which one it is
+func (lex *Lexer) readNumber() (string, token.TokenType) {
 	startPos := lex.pos
+	tokType := token.INTEGER
+	lex.readDigits()
+	if lex.char == '.' && isDigit(lex.peekChar()) { // 1.foo is not a float
+		tokType = token.FLOAT
+		lex.readChar()
+		lex.readDigits()
+	}
+	if lex.char == 'e' || lex.char == 'E' {
+		next := lex.peekChar()
+		if isDigit(next) || ((next == '+' || next == '-') && lex.peekPos+1 < len(lex.input) && isDigit(lex.input[lex.peekPos+1])) {
+			tokType = token.FLOAT
+			lex.readChar() // skip e
+			if lex.char == '+' || lex.char == '-' {
+				lex.readChar()
+			}
+			lex.readDigits()
+		}
+	}
+	return lex.input[startPos:lex.pos], tokType
+}
+
+func (lex *Lexer) readDigits() {
 	for isDigit(lex.char) {
 		lex.readChar()
 	}
-	return lex.input[startPos:lex.pos]
 }
 
 func (lex *Lexer) readChar() {
@@ -143,8 +239,10 @@ func (lex *Lexer) skipSpace() {
 
 func (lex *Lexer) skipNewLine() {
 	for lex.char == '\n' || lex.char == '\r' {
+		if lex.char == '\n' { // \r\n is one line
+			lex.newLine()
+		}
 		lex.readChar()
-		lex.Line++
 		if lex.char == ' ' || lex.char == '\t' {
 			lex.skipSpace()
 		}
@@ -156,7 +254,7 @@ func (lex *Lexer) isComment() bool {
 }
 
 func (lex *Lexer) skipComment() {
-	for lex.char != 10 { // 10 ascii code for new line
+	for lex.char != 10 && !lex.isEOF() { // 10 ascii code for new line. comment on the last line may not have it
 		lex.readChar()
 	}
 }
@@ -177,12 +275,73 @@ func isDigit(char byte) bool {
 	return char >= '0' && char <= '9' // '0' corresponds to ASCII value 48. '9' corresponds to ASCII value 57.
 }
 
-func (lex *Lexer) readString() string {
-	var str string
+// readString read "..." string. \n, \t, \r, \", \\ and \` is replaced with the character it represent, other escape is kept as it is.
+// it return false if the input end before the closing quote
+func (lex *Lexer) readString() (string, bool) {
+	var b strings.Builder
 	lex.readChar()
-	for lex.char != 34 {
-		str += string(lex.char)
+	for lex.char != '"' {
+		if lex.isEOF() {
+			return "", false
+		}
+		if lex.char == '\n' {
+			lex.newLine()
+		}
+		if lex.char == '\\' {
+			lex.readChar()
+			if lex.isEOF() {
+				return "", false
+			}
+			if lex.char == '\n' { // backslash before the end of line, the string still continue to the next line
+				lex.newLine()
+			}
+			if esc, ok := escapes[lex.char]; ok {
+				b.WriteByte(esc)
+			} else {
+				b.WriteByte('\\')
+				b.WriteByte(lex.char)
+			}
+			lex.readChar()
+			continue
+		}
+		b.WriteByte(lex.char) // write byte by byte so multi-byte character (é, 👋) stay intact
+		lex.readChar()
+	}
+	return b.String(), true
+}
+
+var escapes = map[byte]byte{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'"':  '"',
+	'\\': '\\',
+	'`':  '`',
+}
+
+// readRawString read `...` string. nothing is escaped and it could span multiple lines
+func (lex *Lexer) readRawString() (string, bool) {
+	lex.readChar()
+	start := lex.pos
+	for lex.char != '`' {
+		if lex.isEOF() {
+			return "", false
+		}
+		if lex.char == '\n' {
+			lex.newLine()
+		}
 		lex.readChar()
 	}
-	return str
+	return lex.input[start:lex.pos], true
+}
+
+// newLine must be called when lex.char is '\n'
+func (lex *Lexer) newLine() {
+	lex.Line++
+	lex.lineStart = lex.pos + 1
+}
+
+// isEOF is true when the whole input is consumed. checking lex.char == 0 is not enough since the input itself could contain NUL byte
+func (lex *Lexer) isEOF() bool {
+	return lex.pos >= len(lex.input)
 }
@@ -258,3 +258,331 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestNumber(t *testing.T) {
+	input := `12 3.14 0.5 1e-3 2.5E10 7e+2 1.foo 4e arr[1]`
+	test := []testStruct{
+		{token.INTEGER, "12"},
+		{token.FLOAT, "3.14"},
+		{token.FLOAT, "0.5"},
+		{token.FLOAT, "1e-3"},
+		{token.FLOAT, "2.5E10"},
+		{token.FLOAT, "7e+2"},
+		{token.INTEGER, "1"},
+		{token.ILLEGAL, "."},
+		{token.IDENT, "foo"},
+		{token.INTEGER, "4"},
+		{token.IDENT, "e"},
+		{token.IDENT, "arr"},
+		{token.LBRACKET, "["},
+		{token.INTEGER, "1"},
+		{token.RBRACKET, "]"},
+		{token.EOF, ""},
+	}
+
+	lex := NewLex(input)
+	for i, tokTest := range test {
+		tok := lex.NextToken()
+		if tok.Type != tokTest.expectedType {
+			t.Fatalf("tokenType wrong at [%d] - expected (%s), got (%s) val(%s)", i, tokTest.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Literal != tokTest.expectedLiteral {
+			t.Fatalf("tokenLiteral wrong at [%d] - expected (%s), got (%s)", i, tokTest.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestLogicalOperator(t *testing.T) {
+	input := `a && b || c dan d atau !e & |`
+	test := []testStruct{
+		{token.IDENT, "a"},
+		{token.DAN, "&&"},
+		{token.IDENT, "b"},
+		{token.ATAU, "||"},
+		{token.IDENT, "c"},
+		{token.DAN, "dan"},
+		{token.IDENT, "d"},
+		{token.ATAU, "atau"},
+		{token.BANG, "!"},
+		{token.IDENT, "e"},
+		{token.ILLEGAL, "&"},
+		{token.ILLEGAL, "|"},
+		{token.EOF, ""},
+	}
+
+	lex := NewLex(input)
+	for i, tokTest := range test {
+		tok := lex.NextToken()
+		if tok.Type != tokTest.expectedType {
+			t.Fatalf("tokenType wrong at [%d] - expected (%s), got (%s) val(%s)", i, tokTest.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Literal != tokTest.expectedLiteral {
+			t.Fatalf("tokenLiteral wrong at [%d] - expected (%s), got (%s)", i, tokTest.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestComparisonOperator(t *testing.T) {
+	input := `a <= b >= c < d > e == f != g <=5`
+	test := []testStruct{
+		{token.IDENT, "a"},
+		{token.LT_SAMA, "<="},
+		{token.IDENT, "b"},
+		{token.GT_SAMA, ">="},
+		{token.IDENT, "c"},
+		{token.LT, "<"},
+		{token.IDENT, "d"},
+		{token.GT, ">"},
+		{token.IDENT, "e"},
+		{token.SAMA, "=="},
+		{token.IDENT, "f"},
+		{token.TIDAK_SAMA, "!="},
+		{token.IDENT, "g"},
+		{token.LT_SAMA, "<="},
+		{token.INTEGER, "5"},
+		{token.EOF, ""},
+	}
+
+	lex := NewLex(input)
+	for i, tokTest := range test {
+		tok := lex.NextToken()
+		if tok.Type != tokTest.expectedType {
+			t.Fatalf("tokenType wrong at [%d] - expected (%s), got (%s) val(%s)", i, tokTest.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Literal != tokTest.expectedLiteral {
+			t.Fatalf("tokenLiteral wrong at [%d] - expected (%s), got (%s)", i, tokTest.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestArithmeticOperator(t *testing.T) {
+	input := `a % b ** c * d div e`
+	test := []testStruct{
+		{token.IDENT, "a"},
+		{token.MODULO, "%"},
+		{token.IDENT, "b"},
+		{token.PANGKAT, "**"},
+		{token.IDENT, "c"},
+		{token.ASTERISK, "*"},
+		{token.IDENT, "d"},
+		{token.DIV, "div"},
+		{token.IDENT, "e"},
+		{token.EOF, ""},
+	}
+
+	lex := NewLex(input)
+	for i, tokTest := range test {
+		tok := lex.NextToken()
+		if tok.Type != tokTest.expectedType {
+			t.Fatalf("tokenType wrong at [%d] - expected (%s), got (%s) val(%s)", i, tokTest.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Literal != tokTest.expectedLiteral {
+			t.Fatalf("tokenLiteral wrong at [%d] - expected (%s), got (%s)", i, tokTest.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestUnicodeString(t *testing.T) {
+	input := `"café" "Selamat pagi 👋" "🇮🇩";`
+	test := []testStruct{
+		{token.STRING, "café"},
+		{token.STRING, "Selamat pagi 👋"},
+		{token.STRING, "🇮🇩"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	lex := NewLex(input)
+	for i, tokTest := range test {
+		tok := lex.NextToken()
+		if tok.Type != tokTest.expectedType {
+			t.Fatalf("tokenType wrong at [%d] - expected (%s), got (%s) val(%s)", i, tokTest.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Literal != tokTest.expectedLiteral {
+			t.Fatalf("tokenLiteral wrong at [%d] - expected (%s), got (%s)", i, tokTest.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestStringEscape(t *testing.T) {
+	input := `"a\nb" "a\tb" "a\rb" "dia berkata \"halo\"" "C:\\kusmala" "\` + "`" + `" "\q" "\\n"`
+	test := []testStruct{
+		{token.STRING, "a\nb"},
+		{token.STRING, "a\tb"},
+		{token.STRING, "a\rb"},
+		{token.STRING, `dia berkata "halo"`},
+		{token.STRING, `C:\kusmala`},
+		{token.STRING, "`"},
+		{token.STRING, `\q`}, // unknown escape is kept as it is
+		{token.STRING, `\n`},
+		{token.EOF, ""},
+	}
+
+	lex := NewLex(input)
+	for i, tokTest := range test {
+		tok := lex.NextToken()
+		if tok.Type != tokTest.expectedType {
+			t.Fatalf("tokenType wrong at [%d] - expected (%s), got (%s) val(%s)", i, tokTest.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Literal != tokTest.expectedLiteral {
+			t.Fatalf("tokenLiteral wrong at [%d] - expected (%q), got (%q)", i, tokTest.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestRawString(t *testing.T) {
+	input := "buat s = `baris satu\n\tbaris \"dua\" \\n\nbaris tiga`;\nbuat x = 1;"
+	test := []struct {
+		testStruct
+		line int
+	}{
+		{testStruct{token.BUAT, "buat"}, 1},
+		{testStruct{token.IDENT, "s"}, 1},
+		{testStruct{token.ASSIGN, "="}, 1},
+		{testStruct{token.STRING, "baris satu\n\tbaris \"dua\" \\n\nbaris tiga"}, 3},
+		{testStruct{token.SEMICOLON, ";"}, 3},
+		{testStruct{token.BUAT, "buat"}, 4},
+		{testStruct{token.IDENT, "x"}, 4},
+	}
+
+	lex := NewLex(input)
+	for i, tokTest := range test {
+		tok := lex.NextToken()
+		if tok.Type != tokTest.expectedType {
+			t.Fatalf("tokenType wrong at [%d] - expected (%s), got (%s) val(%s)", i, tokTest.expectedType, tok.Type, tok.Literal)
+		}
+		if tok.Literal != tokTest.expectedLiteral {
+			t.Fatalf("tokenLiteral wrong at [%d] - expected (%q), got (%q)", i, tokTest.expectedLiteral, tok.Literal)
+		}
+		if lex.Line != tokTest.line {
+			t.Fatalf("lex.Line wrong at [%d] - expected (%d), got (%d)", i, tokTest.line, lex.Line)
+		}
+	}
+}
+
+func TestEscapedNewLine(t *testing.T) {
+	input := "buat s = \"a\\\nb\";\ncetak(1);"
+	test := []struct {
+		lit  string
+		line int
+		col  int
+	}{
+		{"buat", 1, 1},
+		{"s", 1, 6},
+		{"=", 1, 8},
+		{"a\\\nb", 1, 10},
+		{";", 2, 3},
+		{"cetak", 3, 1},
+		{"(", 3, 6},
+	}
+
+	lex := NewLex(input)
+	for i, tt := range test {
+		tok := lex.NextToken()
+		if tok.Literal != tt.lit {
+			t.Fatalf("tokenLiteral wrong at [%d] - expected (%q), got (%q)", i, tt.lit, tok.Literal)
+		}
+		if tok.Start.Line != tt.line || tok.Start.Col != tt.col {
+			t.Fatalf("position wrong at [%d] - expected (%d:%d), got (%d:%d)", i, tt.line, tt.col, tok.Start.Line, tok.Start.Col)
+		}
+	}
+	if lex.Line != 3 {
+		t.Fatalf("lex.Line wrong - expected (3), got (%d)", lex.Line)
+	}
+}
+
+func TestUnterminated(t *testing.T) {
+	test := []struct {
+		input  string
+		expect []testStruct
+	}{
+		{`buat s = "halo`, []testStruct{{token.BUAT, "buat"}, {token.IDENT, "s"}, {token.ASSIGN, "="}, {token.ERROR, "string tidak ditutup di baris 1"}, {token.EOF, ""}}},
+		{"x;\n\"satu\ndua", []testStruct{{token.IDENT, "x"}, {token.SEMICOLON, ";"}, {token.ERROR, "string tidak ditutup di baris 2"}, {token.EOF, ""}}},
+		{"`mentah\n", []testStruct{{token.ERROR, "string tidak ditutup di baris 1"}, {token.EOF, ""}}},
+		{`"akhir\`, []testStruct{{token.ERROR, "string tidak ditutup di baris 1"}, {token.EOF, ""}}},
+		{`"\"`, []testStruct{{token.ERROR, "string tidak ditutup di baris 1"}, {token.EOF, ""}}},
+		{"x; // komentar tanpa baris baru", []testStruct{{token.IDENT, "x"}, {token.SEMICOLON, ";"}, {token.EOF, ""}}},
+		{"//", []testStruct{{token.EOF, ""}}},
+		{"// satu\n// dua", []testStruct{{token.EOF, ""}}},
+	}
+
+	for _, tt := range test {
+		lex := NewLex(tt.input)
+		for i, tokTest := range tt.expect {
+			tok := lex.NextToken()
+			if tok.Type != tokTest.expectedType {
+				t.Fatalf("%q: tokenType wrong at [%d] - expected (%s), got (%s) val(%s)", tt.input, i, tokTest.expectedType, tok.Type, tok.Literal)
+			}
+			if tok.Literal != tokTest.expectedLiteral {
+				t.Fatalf("%q: tokenLiteral wrong at [%d] - expected (%s), got (%s)", tt.input, i, tokTest.expectedLiteral, tok.Literal)
+			}
+		}
+	}
+}
+
+// every token consume at least one byte, so the lexer must reach EOF in at most len(input)+1 call
+func FuzzNextToken(f *testing.F) {
+	for _, seed := range []string{
+		input_three,
+		`"halo`,
+		"`mentah",
+		`"a\`,
+		"// komentar",
+		"x // komentar",
+		"buat s = \"a\\\"b\";",
+		"1.5e+3 ** 2 <= 3 && benar || !salah",
+		"{\"a\": [1, 2][0:1]}",
+		"\x00\"",
+		"😀 é",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		lex := NewLex(input)
+		for i := 0; i <= len(input)+1; i++ {
+			if lex.NextToken().Type == token.EOF {
+				return
+			}
+		}
+		t.Fatalf("lexer did not reach EOF after %d token for input %q", len(input)+2, input)
+	})
+}
+
+func TestTokenPosition(t *testing.T) {
+	input := "buat x = 10;\r\n  cetak(\"é\", x);\n`a\nbc` <= 2"
+	test := []struct {
+		lit        string
+		start, end token.Position
+	}{
+		{"buat", token.Position{Offset: 0, Line: 1, Col: 1}, token.Position{Offset: 4, Line: 1, Col: 5}},
+		{"x", token.Position{Offset: 5, Line: 1, Col: 6}, token.Position{Offset: 6, Line: 1, Col: 7}},
+		{"=", token.Position{Offset: 7, Line: 1, Col: 8}, token.Position{Offset: 8, Line: 1, Col: 9}},
+		{"10", token.Position{Offset: 9, Line: 1, Col: 10}, token.Position{Offset: 11, Line: 1, Col: 12}},
+		{";", token.Position{Offset: 11, Line: 1, Col: 12}, token.Position{Offset: 12, Line: 1, Col: 13}},
+		{"cetak", token.Position{Offset: 16, Line: 2, Col: 3}, token.Position{Offset: 21, Line: 2, Col: 8}},
+		{"(", token.Position{Offset: 21, Line: 2, Col: 8}, token.Position{Offset: 22, Line: 2, Col: 9}},
+		{"é", token.Position{Offset: 22, Line: 2, Col: 9}, token.Position{Offset: 26, Line: 2, Col: 12}},
+		{",", token.Position{Offset: 26, Line: 2, Col: 12}, token.Position{Offset: 27, Line: 2, Col: 13}},
+		{"x", token.Position{Offset: 28, Line: 2, Col: 14}, token.Position{Offset: 29, Line: 2, Col: 15}},
+		{")", token.Position{Offset: 29, Line: 2, Col: 15}, token.Position{Offset: 30, Line: 2, Col: 16}},
+		{";", token.Position{Offset: 30, Line: 2, Col: 16}, token.Position{Offset: 31, Line: 2, Col: 17}},
+		{"a\nbc", token.Position{Offset: 32, Line: 3, Col: 1}, token.Position{Offset: 38, Line: 4, Col: 4}},
+		{"<=", token.Position{Offset: 39, Line: 4, Col: 5}, token.Position{Offset: 41, Line: 4, Col: 7}},
+		{"2", token.Position{Offset: 42, Line: 4, Col: 8}, token.Position{Offset: 43, Line: 4, Col: 9}},
+		{"", token.Position{Offset: 43, Line: 4, Col: 9}, token.Position{Offset: 43, Line: 4, Col: 9}},
+	}
+
+	lex := NewLex(input)
+	for i, tt := range test {
+		tok := lex.NextToken()
+		if tok.Literal != tt.lit {
+			t.Fatalf("tokenLiteral wrong at [%d] - expected (%q), got (%q)", i, tt.lit, tok.Literal)
+		}
+		if tok.Start != tt.start {
+			t.Fatalf("tok.Start wrong at [%d] %q - expected %+v, got %+v", i, tt.lit, tt.start, tok.Start)
+		}
+		if tok.End != tt.end {
+			t.Fatalf("tok.End wrong at [%d] %q - expected %+v, got %+v", i, tt.lit, tt.end, tok.End)
+		}
+	}
+}
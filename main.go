@@ -14,6 +14,6 @@ func main() {
 	if len(arg) > 1 {
 		file.Read(arg, DEV_MODE)
 	} else {
-		repl.Start(os.Stdin, os.Stdout, DEV_MODE)
+		os.Exit(repl.Start(os.Stdin, os.Stdout, DEV_MODE))
 	}
 }
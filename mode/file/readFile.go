@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/vricap/kusmala/ast"
+	"github.com/vricap/kusmala/diagnostic"
 	"github.com/vricap/kusmala/evaluator"
 	"github.com/vricap/kusmala/lexer"
 	"github.com/vricap/kusmala/object"
@@ -19,24 +20,50 @@ func Read(arg []string, DEV_MODE bool) {
 		}
 		runText(arg[2], false)
 	} else {
-		tree := readFile(arg[1], DEV_MODE)
-		env := object.NewEnv()
-		evals := evaluator.Eval(tree, env)
-		if evals != nil {
-			// printEval(evals)
+		printTree, args := parseArgs(arg[2:])
+		tree, src := readFile(arg[1], DEV_MODE)
+		env := newEnv(args)
+		res := evaluator.Eval(tree, env)
+		if printTree {
+			parser.PrintTree(tree.Statements)
 		}
-		if len(arg) > 2 {
-			switch arg[2] {
-			case "-tree":
-				parser.PrintTree(tree.Statements)
-			default:
-				log.Fatal("Argumen tidak diketahui!")
-			}
+		if res.Err != nil {
+			printEvalError(res.Err, src)
 		}
+		if res.Keluar != nil {
+			os.Exit(res.Keluar.Kode)
+		}
+	}
+}
+
+// parseArgs split the arguments after the file path. everything after "--" is passed to the script as it is
+func parseArgs(arg []string) (printTree bool, args []string) {
+	for i, a := range arg {
+		switch a {
+		case "-tree":
+			printTree = true
+		case "--":
+			return printTree, arg[i+1:]
+		default:
+			log.Fatal("Argumen tidak diketahui!")
+		}
+	}
+	return printTree, nil
+}
+
+// newEnv create the global environment with the std streams and the script arguments in 'argumen'
+func newEnv(args []string) *object.Environment {
+	env := object.NewEnvWithContext(object.NewContext(os.Stdin, os.Stdout, os.Stderr))
+	el := []object.Object{}
+	for _, a := range args {
+		el = append(el, &object.String{Value: a})
 	}
+	env.Set("argumen", &object.Array{El: el})
+	return env
 }
 
-func readFile(path string, DEV_MODE bool) *ast.Tree {
+// readFile parse the file. the source code is returned too for rendering the error
+func readFile(path string, DEV_MODE bool) (*ast.Tree, string) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		log.Fatalf("Mengalami masalah saat membuka file tersebut:\n%s", err)
@@ -45,16 +72,17 @@ func readFile(path string, DEV_MODE bool) *ast.Tree {
 		log.Fatal("File merupakan bukan file kusmala. File kusmala ektensi '.km'")
 	}
 
-	lex := lexer.NewLex(string(data))
+	src := string(data)
+	lex := lexer.NewLex(src)
 	pars := parser.NewPars(lex)
 	tree := pars.ConstructTree()
 	if len(pars.DevErrors) != 0 && DEV_MODE {
 		printDevError(pars.DevErrors)
 	}
-	if len(pars.Errors) != 0 {
-		printParsingError(pars.Errors)
+	if len(pars.Diagnostics) != 0 {
+		printParsingError(pars.Diagnostics, src)
 	}
-	return tree
+	return tree, src
 }
 
 func isKusmalaFile(n string) bool {
@@ -62,10 +90,9 @@ func isKusmalaFile(n string) bool {
 	return x == ".km"
 }
 
-func printParsingError(err []string) {
-	fmt.Println("Pesan error mungkin tidak akurat :)")
-	for _, e := range err {
-		fmt.Println("\t" + e)
+func printParsingError(diags []*diagnostic.Diagnostic, src string) {
+	for _, d := range diags {
+		fmt.Fprint(os.Stderr, d.Render(src))
 	}
 	os.Exit(1)
 }
@@ -77,6 +104,15 @@ func printDevError(err []string) {
 	os.Exit(1)
 }
 
+// printEvalError report the runtime error to stderr and exit with non-zero status so the shell know the program failed
+func printEvalError(err *object.Error, src string) {
+	fmt.Fprint(os.Stderr, err.Diagnostic().Render(src))
+	for _, frame := range err.StackTrace() {
+		fmt.Fprintln(os.Stderr, "\t"+frame)
+	}
+	os.Exit(1)
+}
+
 func printEval(evals []object.Object) {
 	for _, eval := range evals {
 		fmt.Printf("%s\n", eval.Inspect())
@@ -90,10 +126,15 @@ func runText(text string, DEV_MODE bool) {
 	if len(pars.DevErrors) != 0 && DEV_MODE {
 		printDevError(pars.DevErrors)
 	}
-	if len(pars.Errors) != 0 {
-		printParsingError(pars.Errors)
+	if len(pars.Diagnostics) != 0 {
+		printParsingError(pars.Diagnostics, text)
 	}
 
-	env := object.NewEnv()
-	evaluator.Eval(tree, env)
+	res := evaluator.Eval(tree, newEnv(nil))
+	if res.Err != nil {
+		printEvalError(res.Err, text)
+	}
+	if res.Keluar != nil {
+		os.Exit(res.Keluar.Kode)
+	}
 }
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"os/user"
+	"strings"
 
+	"github.com/vricap/kusmala/diagnostic"
 	"github.com/vricap/kusmala/evaluator"
 	"github.com/vricap/kusmala/lexer"
 	"github.com/vricap/kusmala/object"
@@ -14,25 +16,34 @@ import (
 
 const PROMPT = ">> "
 
-func Start(in io.Reader, out io.Writer, DEV_MODE bool) {
+// Start run the REPL until the input end or the program call keluar. it return the exit status for the process
+func Start(in io.Reader, out io.Writer, DEV_MODE bool) int {
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
 	}
 
-	fmt.Printf("Halo %s! Ini adalah bahasa pemrograman KUSMALA!\n", user.Username)
-	fmt.Println("Silahkan untuk mengetik program.")
-	scanner := bufio.NewScanner(in)
-	env := object.NewEnv()
+	fmt.Fprintf(out, "Halo %s! Ini adalah bahasa pemrograman KUSMALA!\n", user.Username)
+	fmt.Fprintln(out, "Silahkan untuk mengetik program.")
+	// the REPL and the program share the same reader, so the line read by the program (e.g with masukan) is not lost in the REPL buffer
+	reader := bufio.NewReader(in)
+	env := object.NewEnvWithContext(object.NewContext(reader, out, out))
+	// every input is kept, and the line number keep counting across inputs. so error inside a function defined in the previous input still point to the right source line
+	history := ""
+	line := 1
 
 	for {
-		fmt.Printf(PROMPT)
-		scanned := scanner.Scan()
-		if !scanned {
-			return
+		fmt.Fprint(out, PROMPT)
+		input, err := reader.ReadString('\n')
+		if err != nil && input == "" {
+			return 0
 		}
-		input := scanner.Text()
-		lex := lexer.NewLex(input)
+		if !strings.HasSuffix(input, "\n") {
+			input += "\n"
+		}
+		history += input
+		lex := lexer.NewLexFromLine(input, line)
+		line += strings.Count(input, "\n")
 		pars := parser.NewPars(lex)
 		tree := pars.ConstructTree()
 
@@ -41,13 +52,17 @@ func Start(in io.Reader, out io.Writer, DEV_MODE bool) {
 			continue
 		}
 
-		if len(pars.Errors) != 0 {
-			printParsingError(pars.Errors, out)
+		if len(pars.Diagnostics) != 0 {
+			printParsingError(pars.Diagnostics, history, out)
 			continue
 		}
-		evals := evaluator.Eval(tree, env)
-		if evals != nil {
-			printEval(evals, out)
+		res := evaluator.Eval(tree, env)
+		printEval(res.Values, out)
+		if res.Err != nil {
+			printEvalError(res.Err, history, out)
+		}
+		if res.Keluar != nil { // keluar() end the REPL session
+			return res.Keluar.Kode
 		}
 		// parser.PrintTree(tree.Statements)
 
@@ -60,10 +75,16 @@ func printEval(evals []object.Object, out io.Writer) {
 	}
 }
 
-func printParsingError(err []string, out io.Writer) {
-	fmt.Println("Pesan error mungkin tidak akurat :)")
-	for _, e := range err {
-		io.WriteString(out, "\t"+e+"\n")
+func printEvalError(err *object.Error, src string, out io.Writer) {
+	io.WriteString(out, err.Diagnostic().Render(src))
+	for _, frame := range err.StackTrace() {
+		io.WriteString(out, "\t"+frame+"\n")
+	}
+}
+
+func printParsingError(diags []*diagnostic.Diagnostic, src string, out io.Writer) {
+	for _, d := range diags {
+		io.WriteString(out, d.Render(src))
 	}
 }
 
@@ -1,17 +1,24 @@
 package object
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/vricap/kusmala/ast"
+	"github.com/vricap/kusmala/diagnostic"
+	"github.com/vricap/kusmala/token"
 )
 
 type ObjectType string
 
 const (
 	OBJECT_INTEGER    ObjectType = "INTEGER"
+	OBJECT_FLOAT                 = "FLOAT"
 	OBJECT_BOOLEAN               = "BOOLEAN"
 	OBJECT_NIL                   = "NIL"
 	OBJECT_KEMBALIKAN            = "OBJECT_KEMBALIKAN"
@@ -19,8 +26,12 @@ const (
 	OBJECT_STRING                = "STRING"
 	OBJECT_FUNGSI                = "FUNGSI"
 	OBJECT_JIKA                  = "JIKA"
-	OBEJCT_BUILTIN               = "BUILTIN"
+	OBJECT_BUILTIN               = "BUILTIN"
 	OBJECT_ARRAY                 = "ARRAY"
+	OBJECT_HENTI                 = "HENTI"
+	OBJECT_LANJUT                = "LANJUT"
+	OBJECT_KAMUS                 = "KAMUS"
+	OBJECT_KELUAR                = "KELUAR"
 )
 
 type Object interface {
@@ -44,6 +55,26 @@ func (i *Integer) Line() int {
 	return i.Ln
 }
 
+type Float struct {
+	Value float64
+	Ln    int
+}
+
+// Inspect always show the decimal point so float is distinguishable from integer. e.g 3.0 instead of 3
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEnN") { // NaN and Inf doesn't need it either
+		s += ".0"
+	}
+	return s
+}
+func (f *Float) Type() ObjectType {
+	return OBJECT_FLOAT
+}
+func (f *Float) Line() int {
+	return f.Ln
+}
+
 type Boolean struct {
 	Value bool
 	Ln    int
@@ -93,18 +124,109 @@ func (i *Kembalikan) Line() int {
 	return i.Ln
 }
 
+// Henti and Lanjut is used to unwind the loop body, just like Kembalikan unwind the function body
+type Henti struct {
+	Ln   int
+	Span token.Span // the position of the statement, for the error when it's used outside loop
+}
+
+func (h *Henti) Inspect() string {
+	return "henti"
+}
+func (h *Henti) Type() ObjectType {
+	return OBJECT_HENTI
+}
+func (h *Henti) Line() int {
+	return h.Ln
+}
+
+type Lanjut struct {
+	Ln   int
+	Span token.Span // the position of the statement, for the error when it's used outside loop
+}
+
+func (l *Lanjut) Inspect() string {
+	return "lanjut"
+}
+func (l *Lanjut) Type() ObjectType {
+	return OBJECT_LANJUT
+}
+func (l *Lanjut) Line() int {
+	return l.Ln
+}
+
+// Keluar is the request from keluar(kode) to terminate the program with the given status
+type Keluar struct {
+	Kode int
+	Ln   int
+}
+
+func (k *Keluar) Inspect() string {
+	return fmt.Sprintf("keluar(%d)", k.Kode)
+}
+func (k *Keluar) Type() ObjectType {
+	return OBJECT_KELUAR
+}
+func (k *Keluar) Line() int {
+	return k.Ln
+}
+
+// Error is a kusmala runtime error. it stop the evaluation and unwind up to evaluator.Eval
 type Error struct {
-	Msg string
+	Msg   string // the message without the position. e.g: kesalahan tipe dekat '1 + benar'
+	Ln    int
+	Span  token.Span // where in the source code the error happen. zero if unknown
+	Stack []Frame    // the function calls the error unwind through, innermost first
+	Code  string     // error code, see the diagnostic package
+	Hint  string     // optional suggestion to fix the error
+}
+
+// Frame is one function call in the error stack trace
+type Frame struct {
+	Fungsi string // the function name
+	CallLn int    // the line where the function was called
 }
 
+// the stack trace of deep recursion is cut in the middle if it has more frame than this
+const maxStackFrames = 10
+
 func (e *Error) Inspect() string {
-	return "ERROR di baris " + e.Msg
+	return fmt.Sprintf("ERROR di baris %d: %s", e.Ln, e.Msg)
+}
+
+// Diagnostic convert the error so it could be rendered with the source code
+func (e *Error) Diagnostic() *diagnostic.Diagnostic {
+	code := e.Code
+	if code == "" {
+		code = diagnostic.KESALAHAN_RUNTIME
+	}
+	span := e.Span
+	if span.IsZero() { // e.g error that is not created from a node, at least the line is known
+		span.Start.Line = e.Ln
+	}
+	return &diagnostic.Diagnostic{Code: code, Msg: e.Msg, Span: span, Hint: e.Hint}
+}
+
+// StackTrace render the call stack, one line per frame. e.g: di fungsi faktorial (baris 5) dipanggil dari baris 12
+func (e *Error) StackTrace() []string {
+	lines := []string{}
+	ln := e.Ln // the line inside the innermost function is where the error happen
+	for i, f := range e.Stack {
+		if len(e.Stack) > maxStackFrames && i == maxStackFrames/2 {
+			lines = append(lines, fmt.Sprintf("... %d pemanggilan lainnya ...", len(e.Stack)-maxStackFrames))
+		}
+		if len(e.Stack) <= maxStackFrames || i < maxStackFrames/2 || i >= len(e.Stack)-maxStackFrames/2 {
+			lines = append(lines, fmt.Sprintf("di fungsi %s (baris %d) dipanggil dari baris %d", f.Fungsi, ln, f.CallLn))
+		}
+		ln = f.CallLn // the line inside the caller is where it call the function
+	}
+	return lines
 }
 func (e *Error) Type() ObjectType {
 	return OBJECT_ERR
 }
-func (i *Error) Line() int {
-	return 0
+func (e *Error) Line() int {
+	return e.Ln
 }
 
 type String struct {
@@ -122,36 +244,79 @@ func (s *String) Line() int {
 	return s.Ln
 }
 
+// Environment is a scope. every function call create a new Environment whose Master is the Environment the function was defined in, so the chain of Master is the lexical scope of the code.
+// block (jika, selama, untuk) doesn't create new scope, 'buat' inside a block define the variable in the enclosing function (or global) scope
 type Environment struct {
 	store  map[string]Object
 	Master *Environment // the master Environment of this Environment if any
+	ctx    *Context
 }
 
+// NewEnv create the global Environment that use the process stdin, stdout and stderr
 func NewEnv() *Environment {
+	return NewEnvWithContext(NewContext(os.Stdin, os.Stdout, os.Stderr))
+}
+
+// NewEnvWithContext create the global Environment with the given I/O. every child Environment share the same Context
+func NewEnvWithContext(ctx *Context) *Environment {
 	s := map[string]Object{}
-	return &Environment{store: s}
+	return &Environment{store: s, ctx: ctx}
 }
 
 func NewChildEnv(master *Environment) *Environment {
-	child := NewEnv()
+	child := NewEnvWithContext(master.ctx)
 	child.Master = master
 	return child
 }
 
+func (e *Environment) Context() *Context {
+	return e.ctx
+}
+
+// Get look up the name from this Environment up to the outermost one. the nearest binding win
 func (e *Environment) Get(name string) (Object, bool) {
-	obj, ok := e.store[name]
-	if e.Master != nil && !ok { // if the ident if not exist in this env AND this env have master, we will return the master one.
-		obj, ok := e.Master.store[name]
-		return obj, ok
+	for env := e; env != nil; env = env.Master {
+		if obj, ok := env.store[name]; ok {
+			return obj, true
+		}
 	}
-	return obj, ok
+	return nil, false
 }
+
+// Set define the name in this Environment. if the name exist in the master Environment, it is shadowed, not changed
 func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
 	return val
 }
 
+// Assign change the value of the nearest existing binding of name. it return false if the name is not defined anywhere in the chain
+func (e *Environment) Assign(name string, val Object) bool {
+	for env := e; env != nil; env = env.Master {
+		if _, ok := env.store[name]; ok {
+			env.store[name] = val
+			return true
+		}
+	}
+	return false
+}
+
+// Context is the I/O of the running program. cetak and the I/O builtins must use this instead of the os package, so the REPL and test could redirect it
+type Context struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	Stdin  *bufio.Reader
+}
+
+func NewContext(stdin io.Reader, stdout io.Writer, stderr io.Writer) *Context {
+	in, ok := stdin.(*bufio.Reader)
+	if !ok {
+		in = bufio.NewReader(stdin)
+	}
+	return &Context{Stdout: stdout, Stderr: stderr, Stdin: in}
+}
+
 type FungsiLiteral struct {
+	Name  string // the name the function first bound to with buat. empty for anonymous function
 	Param []*ast.Identifier
 	Body  *ast.BlockStatement
 	Env   *Environment
@@ -180,6 +345,25 @@ func (fl *FungsiLiteral) Inspect() string {
 	return out.String()
 }
 
+// BuiltinFungsi is the Go implementation of a builtin. ln is the line of the call, so the builtin could report error at the right place
+type BuiltinFungsi func(env *Environment, ln int, args ...Object) Object
+
+// Builtin is function implemented in Go. it is a first-class value just like FungsiLiteral
+type Builtin struct {
+	Name string
+	Fn   BuiltinFungsi
+}
+
+func (b *Builtin) Type() ObjectType {
+	return OBJECT_BUILTIN
+}
+func (b *Builtin) Inspect() string {
+	return "fungsi bawaan " + b.Name
+}
+func (b *Builtin) Line() int {
+	return 0
+}
+
 type Array struct {
 	El []Object
 	Ln int
@@ -204,3 +388,81 @@ func (a *Array) Inspect() string {
 func (a *Array) Line() int {
 	return a.Ln
 }
+
+// HashKey is what we use as the key of the Go map inside Kamus. two object with the same type and value will have the same HashKey
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+	Str   string // string key use the string itself, so two different strings never collide
+}
+
+// Hashable is implemented by object that could be used as kamus key
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+func (b *Boolean) HashKey() HashKey {
+	var v uint64
+	if b.Value {
+		v = 1
+	}
+	return HashKey{Type: b.Type(), Value: v}
+}
+
+func (s *String) HashKey() HashKey {
+	return HashKey{Type: s.Type(), Str: s.Value}
+}
+
+// HashPair keep the original key object so we could print it
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+type Kamus struct {
+	Pairs map[HashKey]HashPair
+	Keys  []HashKey // insertion order of the keys, so Inspect and iteration is deterministic
+	Ln    int
+}
+
+func NewKamus(ln int) *Kamus {
+	return &Kamus{Pairs: map[HashKey]HashPair{}, Ln: ln}
+}
+
+// Set insert or replace the value of key. replacing a value doesn't change the key position
+func (k *Kamus) Set(key Hashable, val Object) {
+	hk := key.HashKey()
+	if _, ok := k.Pairs[hk]; !ok {
+		k.Keys = append(k.Keys, hk)
+	}
+	k.Pairs[hk] = HashPair{Key: key, Value: val}
+}
+
+func (k *Kamus) Get(key Hashable) (Object, bool) {
+	pair, ok := k.Pairs[key.HashKey()]
+	return pair.Value, ok
+}
+
+func (k *Kamus) Type() ObjectType {
+	return OBJECT_KAMUS
+}
+func (k *Kamus) Inspect() string {
+	var b bytes.Buffer
+	pairs := []string{}
+	for _, hk := range k.Keys {
+		pair := k.Pairs[hk]
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+	b.WriteString("{")
+	b.WriteString(strings.Join(pairs, ", "))
+	b.WriteString("}")
+	return b.String()
+}
+func (k *Kamus) Line() int {
+	return k.Ln
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/vricap/kusmala/ast"
+	"github.com/vricap/kusmala/diagnostic"
 	"github.com/vricap/kusmala/lexer"
 	"github.com/vricap/kusmala/token"
 )
@@ -18,36 +19,51 @@ type infixParsFunc func(ast.Expression) ast.Expression
 const (
 	_ int = iota // since its zero, so we dont need that
 	LOWEST
+	LOGICAL_OR  // || or atau
+	LOGICAL_AND // && or dan
 	EQUALS      // ==
-	LESSGREATER // > or <
+	LESSGREATER // >, <, >= or <=
 	SUM         // +
-	PRODUCT     // *
+	PRODUCT     // *, /, % or div
 	PREFIX      // -X or !X
+	POWER       // ** bind tighter than prefix, so -2 ** 2 is -(2 ** 2)
 	CALL        // myFunction(X)
 	INDEX       // array[]
 )
 
 var precedence map[token.TokenType]int = map[token.TokenType]int{
+	token.ATAU:       LOGICAL_OR,
+	token.DAN:        LOGICAL_AND,
 	token.SAMA:       EQUALS,
 	token.TIDAK_SAMA: EQUALS,
 	token.LT:         LESSGREATER,
 	token.GT:         LESSGREATER,
+	token.LT_SAMA:    LESSGREATER,
+	token.GT_SAMA:    LESSGREATER,
 	token.PLUS:       SUM,
 	token.MINUS:      SUM,
 	token.SLASH:      PRODUCT,
 	token.ASTERISK:   PRODUCT,
+	token.MODULO:     PRODUCT,
+	token.DIV:        PRODUCT,
+	token.PANGKAT:    POWER,
 	token.LPAREN:     CALL,
 	token.LBRACKET:   INDEX,
 }
 
 type Parser struct {
-	lex       *lexer.Lexer
-	Errors    []string
-	DevErrors []string
+	lex         *lexer.Lexer
+	Errors      []string
+	Diagnostics []*diagnostic.Diagnostic // the same error as Errors, with the position for rendering
+	DevErrors   []string
 
 	currToken token.Token
 	peekToken token.Token
 
+	// panic mode. after an error the parser is confused, so the error after it is not reported until synchronize find the next statement
+	panicMode bool
+	errTok    token.Token // the token of the error that start the panic mode
+
 	// a map to define the token type assosiations with prefix or infix function
 	prefixParsMap map[token.TokenType]prefixParsFunc
 	infixParsMap  map[token.TokenType]infixParsFunc
@@ -67,19 +83,20 @@ func NewPars(lex *lexer.Lexer) *Parser {
 	pars.prefixParsMap = map[token.TokenType]prefixParsFunc{} // initialize empty prefixParsMap map
 	pars.registerPrefix(token.IDENT, pars.parsIdent)          // register token type ident with parsIdent function which match prefixParsFunc function type
 	pars.registerPrefix(token.INTEGER, pars.parsIntegerLiteral)
+	pars.registerPrefix(token.FLOAT, pars.parsFloatLiteral)
 	pars.registerPrefix(token.BANG, pars.parsPrefix)
 	pars.registerPrefix(token.MINUS, pars.parsPrefix)
 	pars.registerPrefix(token.BENAR, pars.parsBooleanLiteral)
 	pars.registerPrefix(token.SALAH, pars.parsBooleanLiteral)
 	pars.registerPrefix(token.FUNGSI, pars.parsFungsiLiteral)
-	pars.registerPrefix(token.PANJANG, pars.parsPanjangFungsi)
 	pars.registerPrefix(token.STRING, pars.parsStringLiteral)
 	pars.registerPrefix(token.LBRACKET, pars.parsArrayLiteral)
+	pars.registerPrefix(token.LPAREN, pars.parsGroupedExpression)
+	pars.registerPrefix(token.LBRACE, pars.parsKamusLiteral)
+	pars.registerPrefix(token.ERROR, pars.parsErrorToken)
 
 	// i decide if-else is a statement and NOT a expression
 	// pars.registerPrefix(token.JIKA, pars.parsJikaExpression)
-	// kusmala does not grouped expression... i dont know how to implement it :(
-	// pars.registerPrefix(token.LPAREN, pars.parseGroupedExpression)
 
 	// INFIX EXPRESSION
 	pars.infixParsMap = map[token.TokenType]infixParsFunc{}
@@ -87,10 +104,17 @@ func NewPars(lex *lexer.Lexer) *Parser {
 	pars.registerInfix(token.MINUS, pars.parsInfix)
 	pars.registerInfix(token.ASTERISK, pars.parsInfix)
 	pars.registerInfix(token.SLASH, pars.parsInfix)
+	pars.registerInfix(token.MODULO, pars.parsInfix)
+	pars.registerInfix(token.DIV, pars.parsInfix)
+	pars.registerInfix(token.PANGKAT, pars.parsInfix)
 	pars.registerInfix(token.LT, pars.parsInfix)
 	pars.registerInfix(token.GT, pars.parsInfix)
+	pars.registerInfix(token.LT_SAMA, pars.parsInfix)
+	pars.registerInfix(token.GT_SAMA, pars.parsInfix)
 	pars.registerInfix(token.SAMA, pars.parsInfix)
 	pars.registerInfix(token.TIDAK_SAMA, pars.parsInfix)
+	pars.registerInfix(token.DAN, pars.parsInfix)
+	pars.registerInfix(token.ATAU, pars.parsInfix)
 	pars.registerInfix(token.LPAREN, pars.parsCallExpression)
 	pars.registerInfix(token.LBRACKET, pars.parsIndexExpression)
 	return pars
@@ -104,7 +128,15 @@ func (pars *Parser) parsNextToken() {
 func (pars *Parser) ConstructTree() *ast.Tree {
 	statement := []ast.Statement{}
 	for pars.currToken.Type != token.EOF {
+		start := pars.currToken
 		statement = append(statement, pars.parsStatement())
+		if pars.panicMode {
+			pars.synchronize(start)
+			if pars.expectCurr(token.RBRACE) && pars.currToken.Start.Offset == pars.errTok.Start.Offset { // '}' without block. it's already reported
+				pars.parsNextToken()
+			}
+			continue
+		}
 		pars.parsNextToken()
 	}
 	return &ast.Tree{Statements: statement}
@@ -114,7 +146,9 @@ func (pars *Parser) ConstructTree() *ast.Tree {
 *			STATEMENT PARSING			   *
 *******************************************/
 
-func (pars *Parser) parsStatement() ast.Statement {
+func (pars *Parser) parsStatement() (stmt ast.Statement) {
+	start := pars.currToken
+	defer func() { pars.setPos(stmt, start) }()
 	switch pars.currToken.Type {
 	case token.BUAT:
 		return pars.parsBuatStatement()
@@ -124,6 +158,14 @@ func (pars *Parser) parsStatement() ast.Statement {
 		return pars.parsJikaStatement()
 	case token.CETAK:
 		return pars.parsCetakStatement()
+	case token.SELAMA:
+		return pars.parsSelamaStatement()
+	case token.UNTUK:
+		return pars.parsUntukStatement()
+	case token.HENTI:
+		return pars.parsHentiStatement()
+	case token.LANJUT:
+		return pars.parsLanjutStatement()
 	case token.IDENT:
 		// TODO: find out more about this
 		if pars.expectPeek(token.ASSIGN) { // only run if it is not function call
@@ -139,28 +181,25 @@ func (pars *Parser) parsStatement() ast.Statement {
 func (pars *Parser) parsBuatStatement() *ast.BuatStatement {
 	statement := &ast.BuatStatement{
 		Token: pars.currToken,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 	if !pars.expectPeek(token.IDENT) {
 		// pars.Errors("Sebuah buat statement membutuhkan nama!")
-		pars.peekError(token.IDENT, pars.lex.Line)
+		pars.peekError(token.IDENT)
 	}
 
 	pars.parsNextToken() // currToken now have to be point to ident name
-	statement.Name = &ast.Identifier{
-		Token: pars.currToken,
-		Value: pars.currToken.Literal,
-	}
+	statement.Name = pars.newIdent()
 
 	if !pars.expectPeek(token.ASSIGN) {
 		// pars.Errors("Tanda '=' tidak ditemukan!")
-		pars.peekError(token.ASSIGN, pars.lex.Line)
+		pars.peekError(token.ASSIGN)
 	}
 	pars.parsNextToken()
 
 	_, ok := pars.prefixParsMap[pars.peekToken.Type]
 	if !ok {
-		pars.Errors = append(pars.Errors, fmt.Sprintf("ERROR di baris %d: \n\tMengharapkan Nilai atau Ekspresi, tetapi mendapatkan '%s'.", pars.lex.Line, pars.peekToken.Literal))
+		pars.errorAt(pars.peekToken, diagnostic.EKSPRESI_KOSONG, "", "Mengharapkan Nilai atau Ekspresi, tetapi mendapatkan '%s'.", pars.peekToken.Literal)
 	}
 	pars.parsNextToken()
 	statement.Expression = pars.parsExpression(LOWEST)
@@ -175,7 +214,7 @@ func (pars *Parser) parsBuatStatement() *ast.BuatStatement {
 func (pars *Parser) parsKembalikanStatement() *ast.KembalikanStatement {
 	statement := &ast.KembalikanStatement{
 		Token: pars.currToken,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 	if pars.expectPeek(token.SEMICOLON) {
 		pars.parsNextToken()
@@ -183,7 +222,7 @@ func (pars *Parser) parsKembalikanStatement() *ast.KembalikanStatement {
 	}
 	_, ok := pars.prefixParsMap[pars.peekToken.Type]
 	if !ok {
-		pars.Errors = append(pars.Errors, fmt.Sprintf("ERROR di baris %d: \n\tMengharapkan Nilai atau Ekspresi, tetapi mendapatkan %s.", pars.lex.Line, pars.peekToken.Literal))
+		pars.errorAt(pars.peekToken, diagnostic.EKSPRESI_KOSONG, "", "Mengharapkan Nilai atau Ekspresi, tetapi mendapatkan %s.", pars.peekToken.Literal)
 	}
 	pars.parsNextToken()
 	statement.Expression = pars.parsExpression(LOWEST)
@@ -196,69 +235,183 @@ func (pars *Parser) parsKembalikanStatement() *ast.KembalikanStatement {
 func (pars *Parser) parsJikaStatement() *ast.JikaStatement {
 	jika := &ast.JikaStatement{
 		Token: pars.currToken,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 
 	if !pars.expectPeek(token.LPAREN) {
-		pars.peekError(token.LPAREN, pars.lex.Line)
+		pars.peekError(token.LPAREN)
 	}
 	pars.parsNextToken()
 	if pars.expectPeek(token.RPAREN) {
-		pars.Errors = append(pars.Errors, "Kondisi tidak boleh kosong!")
+		pars.errorAt(pars.peekToken, diagnostic.EKSPRESI_KOSONG, "isi kondisi di antara tanda kurung, contoh: (x > 1)", "Kondisi tidak boleh kosong!")
 	}
 	pars.parsNextToken()
 	jika.Condition = pars.parsExpression(LOWEST)
 
 	if !pars.expectPeek(token.RPAREN) {
-		pars.peekError(token.RPAREN, pars.lex.Line)
+		pars.peekError(token.RPAREN)
 	}
 	pars.parsNextToken()
-	if !pars.expectPeek(token.LBRACE) {
-		pars.peekError(token.LBRACE, pars.lex.Line)
+	jika.JikaBlock = pars.parsBody()
+
+	// TODO: this is stupid
+	if pars.expectPeek(token.LAINNYA) {
+		pars.parsNextToken()
+		jika.LainnyaBlock = pars.parsBody()
+	}
+	return jika
+}
+
+func (pars *Parser) parsSelamaStatement() *ast.SelamaStatement {
+	selama := &ast.SelamaStatement{
+		Token: pars.currToken,
+		Ln:    pars.currToken.Start.Line,
+	}
+
+	if !pars.expectPeek(token.LPAREN) {
+		pars.peekError(token.LPAREN)
 	}
 	pars.parsNextToken()
+	if pars.expectPeek(token.RPAREN) {
+		pars.errorAt(pars.peekToken, diagnostic.EKSPRESI_KOSONG, "isi kondisi di antara tanda kurung, contoh: (x > 1)", "Kondisi tidak boleh kosong!")
+	}
 	pars.parsNextToken()
-	jika.JikaBlock = pars.parsBlockStatement()
+	selama.Condition = pars.parsExpression(LOWEST)
 
-	// TODO: this is stupid
-	if pars.expectPeek(token.LAINNYA) {
+	if !pars.expectPeek(token.RPAREN) {
+		pars.peekError(token.RPAREN)
+	}
+	pars.parsNextToken()
+	selama.Body = pars.parsBody()
+	return selama
+}
+
+func (pars *Parser) parsUntukStatement() ast.Statement {
+	tok := pars.currToken
+	ln := pars.currToken.Start.Line
+	if !pars.expectPeek(token.LPAREN) {
+		pars.peekError(token.LPAREN)
+	}
+	pars.parsNextToken()
+	pars.parsNextToken() // currToken now point to the first token inside the parentheses
+
+	// untuk (x dalam larik) { ... }
+	if pars.expectCurr(token.IDENT) && pars.expectPeek(token.DALAM) {
+		return pars.parsUntukDalamStatement(tok, ln)
+	}
+
+	// untuk (buat i = 0; i < n; i = i + 1) { ... }
+	untuk := &ast.UntukStatement{Token: tok, Ln: ln}
+	if !pars.expectCurr(token.SEMICOLON) {
+		untuk.Init = pars.parsStatement() // buat, reassign and expression statement already consume the ';'
+	}
+	if !pars.expectCurr(token.SEMICOLON) {
+		pars.currError(token.SEMICOLON)
+	}
+	if !pars.expectPeek(token.SEMICOLON) {
+		pars.parsNextToken()
+		untuk.Condition = pars.parsExpression(LOWEST)
+	}
+	if !pars.expectPeek(token.SEMICOLON) {
+		pars.peekError(token.SEMICOLON)
+	}
+	pars.parsNextToken()
+	if !pars.expectPeek(token.RPAREN) {
 		pars.parsNextToken()
+		untuk.Update = pars.parsStatement()
+	}
+	if !pars.expectPeek(token.RPAREN) {
+		pars.peekError(token.RPAREN)
+	}
+	pars.parsNextToken()
+	untuk.Body = pars.parsBody()
+	return untuk
+}
+
+func (pars *Parser) parsUntukDalamStatement(tok token.Token, ln int) *ast.UntukDalamStatement {
+	untuk := &ast.UntukDalamStatement{Token: tok, Ln: ln}
+	untuk.Ident = pars.newIdent()
+	pars.parsNextToken() // currToken now point to dalam
+	pars.parsNextToken()
+	untuk.Iterable = pars.parsExpression(LOWEST)
+	if !pars.expectPeek(token.RPAREN) {
+		pars.peekError(token.RPAREN)
+	}
+	pars.parsNextToken()
+	untuk.Body = pars.parsBody()
+	return untuk
+}
+
+func (pars *Parser) parsHentiStatement() *ast.HentiStatement {
+	henti := &ast.HentiStatement{Token: pars.currToken, Ln: pars.currToken.Start.Line}
+	if pars.expectPeek(token.SEMICOLON) {
+		pars.parsNextToken()
+	}
+	return henti
+}
+
+func (pars *Parser) parsLanjutStatement() *ast.LanjutStatement {
+	lanjut := &ast.LanjutStatement{Token: pars.currToken, Ln: pars.currToken.Start.Line}
+	if pars.expectPeek(token.SEMICOLON) {
+		pars.parsNextToken()
+	}
+	return lanjut
+}
+
+// parsBody parse the '{ ... }' after the header of jika, selama, untuk and fungsi. peekToken should be the '{'.
+// if the header is broken, the rest of it is skipped until the '{' and the body is parsed as usual, so the error inside it is still reported
+func (pars *Parser) parsBody() *ast.BlockStatement {
+	if pars.panicMode {
+		for !pars.expectCurr(token.LBRACE) {
+			if pars.expectCurr(token.SEMICOLON) || pars.expectCurr(token.RBRACE) || pars.expectCurr(token.EOF) { // there's no body, let the statement synchronize
+				return &ast.BlockStatement{Token: pars.currToken, Ln: pars.currToken.Start.Line}
+			}
+			pars.parsNextToken()
+		}
+		pars.panicMode = false
+	} else {
 		if !pars.expectPeek(token.LBRACE) {
-			pars.peekError(token.LBRACE, pars.lex.Line)
+			pars.peekError(token.LBRACE)
+			return &ast.BlockStatement{Token: pars.peekToken, Ln: pars.peekToken.Start.Line}
 		}
 		pars.parsNextToken()
-		pars.parsNextToken()
-		jika.LainnyaBlock = pars.parsBlockStatement()
 	}
-	return jika
+	pars.parsNextToken()
+	return pars.parsBlockStatement()
 }
 
 func (pars *Parser) parsBlockStatement() *ast.BlockStatement {
 	stmnt := &ast.BlockStatement{
 		Token: pars.currToken,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 	for pars.currToken.Type != token.RBRACE {
 		if pars.currToken.Type == token.EOF {
 			break
 		}
+		start := pars.currToken
 		stmnt.Statements = append(stmnt.Statements, pars.parsStatement())
+		if pars.panicMode {
+			pars.synchronize(start)
+			continue
+		}
 		pars.parsNextToken()
 	}
 	// TODO: quick hack
 	if !pars.expectCurr(token.RBRACE) {
-		pars.currError(token.RBRACE, pars.lex.Line)
+		pars.currError(token.RBRACE)
 	}
+	stmnt.SetPos(token.Span{Start: stmnt.Token.Start, End: pars.currToken.End})
 	return stmnt
 }
 
 func (pars *Parser) parsCetakStatement() *ast.CetakStatement {
 	cetak := &ast.CetakStatement{
 		Token: pars.currToken,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 	if !pars.expectPeek(token.LPAREN) {
-		pars.peekError(token.LPAREN, pars.lex.Line)
+		pars.peekError(token.LPAREN)
 	}
 	pars.parsNextToken()
 	if pars.expectPeek(token.RPAREN) {
@@ -274,10 +427,10 @@ func (pars *Parser) parsCetakStatement() *ast.CetakStatement {
 }
 
 func (pars *Parser) parsReassignmentStatement() *ast.ReassignStatement {
-	rs := &ast.ReassignStatement{Token: pars.currToken, Ln: pars.lex.Line}
-	rs.Ident = &ast.Identifier{Token: pars.currToken, Ln: pars.lex.Line, Value: pars.currToken.Literal}
+	rs := &ast.ReassignStatement{Token: pars.currToken, Ln: pars.currToken.Start.Line}
+	rs.Ident = pars.newIdent()
 	if !pars.expectPeek(token.ASSIGN) {
-		pars.peekError(token.ASSIGN, pars.lex.Line)
+		pars.peekError(token.ASSIGN)
 	}
 	pars.parsNextToken()
 	pars.parsNextToken()
@@ -288,16 +441,31 @@ func (pars *Parser) parsReassignmentStatement() *ast.ReassignStatement {
 	return rs
 }
 
+// arr[i] = x. the target is already parsed as index expression, peekToken is the '='
+func (pars *Parser) parsIndexAssignStatement(target *ast.IndexExpression) *ast.IndexAssignStatement {
+	pars.parsNextToken()
+	ia := &ast.IndexAssignStatement{Token: pars.currToken, Ln: target.Ln, Target: target}
+	pars.parsNextToken()
+	ia.NewValue = pars.parsExpression(LOWEST)
+	if pars.expectPeek(token.SEMICOLON) {
+		pars.parsNextToken()
+	}
+	return ia
+}
+
 /*******************************************
 *			EXPRESSION PARSING			   *
 *******************************************/
 
-func (pars *Parser) parsExpressionStatement() *ast.ExpressionStatement {
+func (pars *Parser) parsExpressionStatement() ast.Statement {
 	exprStmnt := &ast.ExpressionStatement{
 		Token: pars.currToken,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 	exprStmnt.Expression = pars.parsExpression(LOWEST)
+	if target, ok := exprStmnt.Expression.(*ast.IndexExpression); ok && pars.expectPeek(token.ASSIGN) {
+		return pars.parsIndexAssignStatement(target)
+	}
 	if pars.peekToken.Type == token.SEMICOLON {
 		pars.parsNextToken() // so currToken point to ; since we don't want to do parsStatement() again - we are in eof
 	}
@@ -307,11 +475,13 @@ func (pars *Parser) parsExpressionStatement() *ast.ExpressionStatement {
 func (pars *Parser) parsExpression(precedence int) ast.Expression {
 	prefix := pars.prefixParsMap[pars.currToken.Type] // check if currToken have function assosiated with that
 	if prefix == nil {
-		pars.Errors = append(pars.Errors, fmt.Sprintf("ERROR di baris %d: \n\tToken tidak diharapkan ditemukan '%s'", pars.lex.Line, pars.currToken.Literal))
+		pars.errorAt(pars.currToken, diagnostic.TOKEN_TIDAK_DIHARAPKAN, "", "Token tidak diharapkan ditemukan '%s'", pars.currToken.Literal)
 		pars.DevErrors = append(pars.DevErrors, fmt.Sprintf("There's not function assosiated with %v, literal: %s", pars.currToken.Type, pars.currToken.Literal))
 		return nil
 	}
+	start := pars.currToken
 	leftExp := prefix() // if so, call it
+	pars.setPos(leftExp, start)
 	for pars.peekToken.Type != token.SEMICOLON && precedence < pars.peekPrecedence() {
 		infix := pars.infixParsMap[pars.peekToken.Type]
 		if infix == nil {
@@ -319,16 +489,24 @@ func (pars *Parser) parsExpression(precedence int) ast.Expression {
 		}
 		pars.parsNextToken()
 		leftExp = infix(leftExp)
+		pars.setPos(leftExp, start) // infix node start from its left operand
 	}
 	return leftExp
 }
 
 func (pars *Parser) parsIdent() ast.Expression { // this signature match the prefixParsFunc function type
-	return &ast.Identifier{
+	return pars.newIdent()
+}
+
+// newIdent create identifier from the current token
+func (pars *Parser) newIdent() *ast.Identifier {
+	ident := &ast.Identifier{
 		Token: pars.currToken,
 		Value: pars.currToken.Literal,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
+	ident.SetPos(token.Span{Start: pars.currToken.Start, End: pars.currToken.End})
+	return ident
 }
 
 func (pars *Parser) parsIntegerLiteral() ast.Expression {
@@ -336,20 +514,36 @@ func (pars *Parser) parsIntegerLiteral() ast.Expression {
 	if err != nil {
 		msg := fmt.Sprintf("could not parse literal: %s to integer", pars.currToken.Literal)
 		pars.DevErrors = append(pars.DevErrors, msg)
+		pars.errorAt(pars.currToken, diagnostic.ANGKA_TIDAK_VALID, "", "Angka '%s' terlalu besar untuk integer", pars.currToken.Literal)
 		return nil
 	}
 	int := &ast.IntegerLiteral{
 		Token: pars.currToken,
 		Value: literal,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 	return int
 }
 
+func (pars *Parser) parsFloatLiteral() ast.Expression {
+	literal, err := strconv.ParseFloat(pars.currToken.Literal, 64)
+	if err != nil {
+		msg := fmt.Sprintf("could not parse literal: %s to float", pars.currToken.Literal)
+		pars.DevErrors = append(pars.DevErrors, msg)
+		pars.errorAt(pars.currToken, diagnostic.ANGKA_TIDAK_VALID, "", "Angka '%s' terlalu besar untuk desimal", pars.currToken.Literal)
+		return nil
+	}
+	return &ast.FloatLiteral{
+		Token: pars.currToken,
+		Value: literal,
+		Ln:    pars.currToken.Start.Line,
+	}
+}
+
 func (pars *Parser) parsBooleanLiteral() ast.Expression {
 	bool := &ast.BooleanLiteral{
 		Token: pars.currToken,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 	if pars.currToken.Literal == "benar" {
 		bool.Value = true
@@ -363,7 +557,7 @@ func (pars *Parser) parsPrefix() ast.Expression {
 	prefix := &ast.PrefixExpression{
 		Token:    pars.currToken,
 		Operator: pars.currToken.Literal,
-		Ln:       pars.lex.Line,
+		Ln:       pars.currToken.Start.Line,
 	}
 	pars.parsNextToken() // currToken now point to the integer
 
@@ -377,32 +571,43 @@ func (pars *Parser) parsInfix(left ast.Expression) ast.Expression {
 		Token:    pars.currToken,
 		Operator: pars.currToken.Literal,
 		Left:     left,
-		Ln:       pars.lex.Line,
+		Ln:       pars.currToken.Start.Line,
 	}
 	precedence := pars.currPrecedence()
+	if exp.Token.Type == token.PANGKAT {
+		precedence-- // ** is right associative. 2 ** 3 ** 2 is 2 ** (3 ** 2)
+	}
 	pars.parsNextToken()
 	exp.Right = pars.parsExpression(precedence)
 
 	return exp
 }
 
-// func (pars *Parser) parseGroupedExpression() ast.Expression {
-// 	pars.parsNextToken()
-// 	exp := pars.parsExpression(LOWEST)
-// 	if !pars.expectPeek(token.RPAREN) {
-// 		return nil
-// 	}
-// 	return exp
-// }
+// (1 + 2) * 3. the parentheses doesn't have its own node, we just parse the inner expression with the lowest precedence so it bind tighter than anything outside it
+func (pars *Parser) parsGroupedExpression() ast.Expression {
+	if pars.expectPeek(token.RPAREN) {
+		pars.errorAt(pars.currToken, diagnostic.EKSPRESI_KOSONG, "", "Ekspresi di dalam tanda kurung tidak boleh kosong")
+		pars.parsNextToken()
+		return nil
+	}
+	pars.parsNextToken()
+	exp := pars.parsExpression(LOWEST)
+	if !pars.expectPeek(token.RPAREN) {
+		pars.peekError(token.RPAREN)
+		return exp
+	}
+	pars.parsNextToken() // currToken now point to ')'
+	return exp
+}
 
 func (pars *Parser) parsFungsiLiteral() ast.Expression {
 	fung := &ast.FungsiExpression{
 		Token: pars.currToken,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 
 	if !pars.expectPeek(token.LPAREN) {
-		pars.peekError(token.LPAREN, pars.lex.Line)
+		pars.peekError(token.LPAREN)
 	}
 	pars.parsNextToken()
 	if pars.expectPeek(token.RPAREN) {
@@ -411,12 +616,7 @@ func (pars *Parser) parsFungsiLiteral() ast.Expression {
 		pars.parsNextToken()
 		fung.Params = pars.parsParams()
 	}
-	if !pars.expectPeek(token.LBRACE) {
-		pars.peekError(token.LBRACE, pars.lex.Line)
-	}
-	pars.parsNextToken()
-	pars.parsNextToken()
-	fung.Body = pars.parsBlockStatement()
+	fung.Body = pars.parsBody()
 	return fung
 }
 
@@ -428,38 +628,22 @@ func (pars *Parser) parsParams() []*ast.Identifier {
 			pars.parsNextToken()
 			continue
 		}
-		if pars.currToken.Type == token.EOF {
+		if pars.currToken.Type != token.IDENT { // including EOF
 			break
 		}
-		iden = append(iden, &ast.Identifier{Token: pars.currToken, Value: pars.currToken.Literal, Ln: pars.lex.Line})
+		iden = append(iden, pars.newIdent())
 		pars.parsNextToken()
 	}
 	// TODO: quick hack
 	if !pars.expectCurr(token.RPAREN) {
-		pars.currError(token.RPAREN, pars.lex.Line)
+		pars.currError(token.RPAREN)
 	}
 	return iden
 }
 
-func (pars *Parser) parsPanjangFungsi() ast.Expression {
-	pf := &ast.PanjangFungsi{Token: pars.currToken, Ln: pars.lex.Line}
-	if !pars.expectPeek(token.LPAREN) {
-		pars.peekError(token.LPAREN, pars.lex.Line)
-	}
-	pars.parsNextToken()
-	pars.parsNextToken()
-	pf.Argument = pars.parsExpression(LOWEST)
-	if !pars.expectPeek(token.RPAREN) {
-		pars.peekError(token.RPAREN, pars.lex.Line)
-	}
-	pars.parsNextToken()
-
-	return pf
-}
-
 // add(1, 2 * 3, 1 - 2)`
 func (pars *Parser) parsCallExpression(ident ast.Expression) ast.Expression {
-	ce := &ast.CallExpression{Token: pars.currToken, Function: ident, Ln: pars.lex.Line}
+	ce := &ast.CallExpression{Token: pars.currToken, Function: ident, Ln: pars.currToken.Start.Line}
 	pars.parsNextToken()
 	ce.Arguments = pars.parsArguments()
 	return ce
@@ -477,26 +661,35 @@ func (pars *Parser) parsArguments() []ast.Expression {
 			break
 		}
 		expr = append(expr, pars.parsExpression(LOWEST))
+		if pars.panicMode { // let synchronize find where to continue
+			return expr
+		}
 		pars.parsNextToken()
 	}
 	// TODO: quick hack
 	if !pars.expectCurr(token.RPAREN) {
-		pars.currError(token.RPAREN, pars.lex.Line)
+		pars.currError(token.RPAREN)
 	}
 	return expr
 }
 
+// the lexer already know what's wrong with the token, just report it
+func (pars *Parser) parsErrorToken() ast.Expression {
+	pars.errorAt(pars.currToken, diagnostic.STRING_TIDAK_DITUTUP, "tutup string dengan tanda kutip yang sama dengan pembukanya", "%s", pars.currToken.Literal)
+	return nil
+}
+
 func (pars *Parser) parsStringLiteral() ast.Expression {
 	str := &ast.StringLiteral{
 		Token: pars.currToken,
 		Value: pars.currToken.Literal,
-		Ln:    pars.lex.Line,
+		Ln:    pars.currToken.Start.Line,
 	}
 	return str
 }
 
 func (pars *Parser) parsArrayLiteral() ast.Expression {
-	arr := &ast.ArrayLiteral{Token: pars.currToken, Ln: pars.lex.Line}
+	arr := &ast.ArrayLiteral{Token: pars.currToken, Ln: pars.currToken.Start.Line}
 	pars.parsNextToken()
 	if pars.expectCurr(token.RBRACKET) {
 		return arr
@@ -506,19 +699,41 @@ func (pars *Parser) parsArrayLiteral() ast.Expression {
 }
 
 func (pars *Parser) parsIndexExpression(left ast.Expression) ast.Expression {
-	index := &ast.IndexExpression{Token: pars.currToken, Ln: pars.lex.Line, Left: left}
+	index := &ast.IndexExpression{Token: pars.currToken, Ln: pars.currToken.Start.Line, Left: left}
 	pars.parsNextToken()
 	if pars.expectCurr(token.RBRACKET) {
+		pars.errorAt(pars.currToken, diagnostic.EKSPRESI_KOSONG, "isi index di antara kurung siku, contoh: arr[0]", "Index tidak boleh kosong")
 		return index
 	}
+	if pars.expectCurr(token.COLON) { // arr[:2]
+		return pars.parsSliceExpression(index, nil)
+	}
 	index.Index = pars.parsExpression(LOWEST)
+	if pars.expectPeek(token.COLON) { // arr[1:2] or arr[1:]
+		pars.parsNextToken()
+		return pars.parsSliceExpression(index, index.Index)
+	}
 	if !pars.expectPeek(token.RBRACKET) {
-		pars.peekError(token.RBRACKET, pars.lex.Line)
+		pars.peekError(token.RBRACKET)
 	}
 	pars.parsNextToken()
 	return index
 }
 
+// currToken is the ':'
+func (pars *Parser) parsSliceExpression(index *ast.IndexExpression, start ast.Expression) ast.Expression {
+	slice := &ast.SliceExpression{Token: index.Token, Ln: index.Ln, Left: index.Left, Start: start}
+	if !pars.expectPeek(token.RBRACKET) {
+		pars.parsNextToken()
+		slice.End = pars.parsExpression(LOWEST)
+	}
+	if !pars.expectPeek(token.RBRACKET) {
+		pars.peekError(token.RBRACKET)
+	}
+	pars.parsNextToken()
+	return slice
+}
+
 func (pars *Parser) parsArrElements() []ast.Expression {
 	el := []ast.Expression{}
 
@@ -531,15 +746,51 @@ func (pars *Parser) parsArrElements() []ast.Expression {
 			break
 		}
 		el = append(el, pars.parsExpression(LOWEST))
+		if pars.panicMode {
+			return el
+		}
 		pars.parsNextToken()
 	}
 	// TODO: quick hack
 	if !pars.expectCurr(token.RBRACKET) {
-		pars.currError(token.RBRACKET, pars.lex.Line)
+		pars.currError(token.RBRACKET)
 	}
 	return el
 }
 
+func (pars *Parser) parsKamusLiteral() ast.Expression {
+	kamus := &ast.KamusLiteral{Token: pars.currToken, Ln: pars.currToken.Start.Line}
+	for !pars.expectPeek(token.RBRACE) {
+		if pars.expectPeek(token.EOF) {
+			break
+		}
+		pars.parsNextToken()
+		key := pars.parsExpression(LOWEST)
+		if !pars.expectPeek(token.COLON) {
+			pars.peekError(token.COLON)
+			return kamus
+		}
+		pars.parsNextToken()
+		pars.parsNextToken()
+		val := pars.parsExpression(LOWEST)
+		kamus.Keys = append(kamus.Keys, key)
+		kamus.Values = append(kamus.Values, val)
+		if !pars.expectPeek(token.RBRACE) {
+			if !pars.expectPeek(token.COMMA) {
+				pars.peekError(token.COMMA)
+				return kamus
+			}
+			pars.parsNextToken()
+		}
+	}
+	if !pars.expectPeek(token.RBRACE) {
+		pars.peekError(token.RBRACE)
+		return kamus
+	}
+	pars.parsNextToken() // currToken now point to '}'
+	return kamus
+}
+
 /*******************************************
 *			HELPER METHOD   			   *
 *******************************************/
@@ -552,14 +803,86 @@ func (pars *Parser) expectCurr(tok token.TokenType) bool {
 	return pars.currToken.Type == tok
 }
 
-func (pars *Parser) peekError(expectTok token.TokenType, l int) {
-	msg := fmt.Sprintf("ERROR di baris %d: \n\tToken selanjutnya mengharapkan %s, tetapi menemukan '%s'", l, expectTok, pars.peekToken.Literal)
-	pars.Errors = append(pars.Errors, msg)
+func (pars *Parser) peekError(expectTok token.TokenType) {
+	pars.errorAt(pars.peekToken, diagnostic.TOKEN_HILANG, expectHint(expectTok), "Token selanjutnya mengharapkan %s, tetapi menemukan '%s'", expectTok, pars.peekToken.Literal)
+}
+
+func (pars *Parser) currError(expectTok token.TokenType) {
+	pars.errorAt(pars.currToken, diagnostic.TOKEN_HILANG, expectHint(expectTok), "Token sekarang mengharapkan %s, tetapi menemukan '%s'", expectTok, pars.currToken.Literal)
 }
 
-func (pars *Parser) currError(expectTok token.TokenType, l int) {
-	msg := fmt.Sprintf("ERROR di baris %d: \n\tToken sekarang mengharapkan %s, tetapi menemukan '%s'", l, expectTok, pars.currToken.Literal)
-	pars.Errors = append(pars.Errors, msg)
+// setPos set the span of the node from the start token until the current token, which is the last token of the node
+func (pars *Parser) setPos(node ast.Node, start token.Token) {
+	if node == nil {
+		return
+	}
+	node.(interface{ SetPos(token.Span) }).SetPos(token.Span{Start: start.Start, End: pars.currToken.End})
+}
+
+// synchronize skip the rest of the broken statement. currToken end up at the first token of the next statement, or at the '}' that close the block, or EOF.
+// start is the first token of the broken statement
+func (pars *Parser) synchronize(start token.Token) {
+	pars.panicMode = false
+	if pars.currToken.Start.Offset == start.Start.Offset { // the statement start with the wrong token. skip it and try again from the next one
+		pars.parsNextToken()
+		return
+	}
+	for {
+		switch {
+		case pars.expectCurr(token.SEMICOLON):
+			pars.parsNextToken()
+			return
+		case pars.expectCurr(token.RBRACE):
+			if pars.currToken.Start.Offset > pars.errTok.Start.Offset { // the '}' after the error close the block of the broken statement, e.g jika (x { }
+				pars.parsNextToken()
+				if pars.expectCurr(token.SEMICOLON) { // buat f = fungsi() { };
+					pars.parsNextToken()
+				}
+			}
+			return
+		case pars.expectCurr(token.EOF), statementStart[pars.currToken.Type]:
+			return
+		}
+		pars.parsNextToken()
+	}
+}
+
+// token that always start a new statement, so it's safe to continue parsing from it
+var statementStart = map[token.TokenType]bool{
+	token.BUAT:       true,
+	token.JIKA:       true,
+	token.SELAMA:     true,
+	token.UNTUK:      true,
+	token.CETAK:      true,
+	token.KEMBALIKAN: true,
+	token.HENTI:      true,
+	token.LANJUT:     true,
+}
+
+// errorAt report error at the position of tok. hint is optional. error while in panic mode is not reported, it's most likely caused by the first one
+func (pars *Parser) errorAt(tok token.Token, code string, hint string, format string, a ...any) {
+	if pars.panicMode {
+		return
+	}
+	pars.panicMode = true
+	pars.errTok = tok
+	msg := fmt.Sprintf(format, a...)
+	pars.Errors = append(pars.Errors, fmt.Sprintf("ERROR di baris %d, kolom %d: \n\t", tok.Start.Line, tok.Start.Col)+msg)
+	pars.Diagnostics = append(pars.Diagnostics, &diagnostic.Diagnostic{
+		Code: code,
+		Msg:  msg,
+		Span: token.Span{Start: tok.Start, End: tok.End},
+		Hint: hint,
+	})
+}
+
+// expectHint suggest the missing delimiter. keyword or identifier doesn't have a hint
+func expectHint(expectTok token.TokenType) string {
+	switch expectTok {
+	case token.LPAREN, token.RPAREN, token.LBRACE, token.RBRACE, token.LBRACKET, token.RBRACKET, token.SEMICOLON, token.COLON, token.COMMA, token.ASSIGN:
+		return fmt.Sprintf("mungkin kurang '%s'", expectTok)
+	}
+	return ""
 }
 
 // register the token type to the eiter prefixParsFunc or infixParsFunc function type
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/vricap/kusmala/ast"
+	"github.com/vricap/kusmala/diagnostic"
 	"github.com/vricap/kusmala/lexer"
 	"github.com/vricap/kusmala/token"
 )
@@ -159,6 +161,32 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestFloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect float64
+	}{
+		{"3.14;", 3.14},
+		{"1e-3;", 0.001},
+		{"2.5E2;", 250},
+	}
+	for _, tt := range tests {
+		tree := constructTree(t, tt.input)
+
+		statement, ok := tree.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("tree.Statements[0] is not *ast.ExpressionStatement. got: %T", tree.Statements[0])
+		}
+		literal, ok := statement.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("Expression not *ast.FloatLiteral. got: %T", statement.Expression)
+		}
+		if literal.Value != tt.expect {
+			t.Errorf("literal.Value is not %g. got: %g", tt.expect, literal.Value)
+		}
+	}
+}
+
 func TestPrefixExpression(t *testing.T) {
 	input := `
 !5;
@@ -222,7 +250,8 @@ func TestInfinixExpression(t *testing.T) {
 		{"1 + 2 * 1", "(1 + (2 * 1))"},
 		{"1 + 2 * 1 + 3", "((1 + (2 * 1)) + 3)"},
 		{"9 > 2 == salah;", "((9 > 2) == salah)"},
-		// {"-(5 + 5)", "(-(5 + 5))"},
+		{"1 + 2 <= 3 == benar", "(((1 + 2) <= 3) == benar)"},
+		{"a >= b * 2", "(a >= (b * 2))"},
 	}
 
 	for _, tt := range infixTest {
@@ -274,44 +303,130 @@ func TestBooleanLiteral(t *testing.T) {
 	}
 }
 
-// func TestOperatorPrecedenceParsing(t *testing.T) {
-// 	tests := []struct {
-// 		input    string
-// 		expected string
-// 	}{
-// 		{
-// 			"1 + (2 + 3) + 4",
-// 			"((1 + (2 + 3)) + 4)",
-// 		},
-// 		{
-// 			"(5 + 5) * 2",
-// 			"((5 + 5) * 2)",
-// 		},
-// 		{
-// 			"2 / (5 + 5)",
-// 			"(2 / (5 + 5))",
-// 		},
-// 		{
-// 			"-(5 + 5)",
-// 			"(-(5 + 5))",
-// 		},
-// 		{
-// 			"!(true == true)",
-// 			"(!(true == true))",
-// 		},
-// 	}
-// 	for _, tt := range tests {
-// 		tree := constructTree(t, tt.input)
-
-// 		stmnt := tree.Statements[0].(*ast.ExpressionStatement)
-// 		exp := stmnt.Expression.(*ast.InfixExpression)
-// 		var buffer bytes.Buffer
-// 		infixTreeToString(exp, &buffer)
-// 		if buffer.String() != tt.expected {
-// 			t.Fatalf("buffer.String() is not %s. got: %s", tt.expected, buffer.String())
-// 		}
-// 	}
-// }
+func TestGroupedExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + (2 + 3) + 4", "((1 + (2 + 3)) + 4)"},
+		{"(5 + 5) * 2", "((5 + 5) * 2)"},
+		{"2 / (5 + 5)", "(2 / (5 + 5))"},
+		{"-(5 + 5)", "(-(5 + 5))"},
+		{"!(benar == benar)", "(!(benar == benar))"},
+		{"((1 + 2))", "(1 + 2)"},
+		{"(1 + 2) * (3 - 4) / 5", "(((1 + 2) * (3 - 4)) / 5)"},
+		{"a * (b + c) < d", "((a * (b + c)) < d)"},
+		{"add((1 + 2) * 3, (4))", "add(((1 + 2) * 3), 4)"},
+		{"arr[(1 + 2) * 3]", "(arr[((1 + 2) * 3)])"},
+		{"(fungsi(x) { x; })(1) * 2", "(fungsi(1) * 2)"},
+	}
+	for _, tt := range tests {
+		tree := constructTree(t, tt.input)
+
+		if len(tree.Statements) != 1 {
+			t.Fatalf("len(tree.Statements) is not 1. got: %d", len(tree.Statements))
+		}
+		stmnt, ok := tree.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("tree.Statements[0] is not *ast.ExpressionStatement. got: %T", tree.Statements[0])
+		}
+		if got := exprToString(stmnt.Expression); got != tt.expected {
+			t.Fatalf("exprToString() is not %s. got: %s", tt.expected, got)
+		}
+	}
+}
+
+func TestArithmeticPrecedence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"2 ** 3 ** 2", "(2 ** (3 ** 2))"},
+		{"-2 ** 2", "(-(2 ** 2))"},
+		{"2 ** -1", "(2 ** (-1))"},
+		{"2 * 3 ** 2", "(2 * (3 ** 2))"},
+		{"a % 2 == 0", "((a % 2) == 0)"},
+		{"1 + 7 div 2", "(1 + (7 div 2))"},
+		{"7 div 2 * 3 % 4", "(((7 div 2) * 3) % 4)"},
+		{"(2 ** 3) ** 2", "((2 ** 3) ** 2)"},
+	}
+	for _, tt := range tests {
+		tree := constructTree(t, tt.input)
+		stmnt, ok := tree.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("tree.Statements[0] is not *ast.ExpressionStatement. got: %T", tree.Statements[0])
+		}
+		if got := exprToString(stmnt.Expression); got != tt.expected {
+			t.Fatalf("exprToString() is not %s. got: %s", tt.expected, got)
+		}
+	}
+}
+
+func TestLogicalPrecedence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a && b || c", "((a && b) || c)"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a dan b atau c", "((a dan b) atau c)"},
+		{"1 < 2 && 3 > 4", "((1 < 2) && (3 > 4))"},
+		{"a == b || !c", "((a == b) || (!c))"},
+		{"(a || b) && c", "((a || b) && c)"},
+	}
+	for _, tt := range tests {
+		tree := constructTree(t, tt.input)
+		stmnt, ok := tree.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("tree.Statements[0] is not *ast.ExpressionStatement. got: %T", tree.Statements[0])
+		}
+		if got := exprToString(stmnt.Expression); got != tt.expected {
+			t.Fatalf("exprToString() is not %s. got: %s", tt.expected, got)
+		}
+	}
+}
+
+func TestGroupedJikaCondition(t *testing.T) {
+	input := `jika ((1 + 2) * 3 > (4)) { 1; }`
+	tree := constructTree(t, input)
+
+	stmnt, ok := tree.Statements[0].(*ast.JikaStatement)
+	if !ok {
+		t.Fatalf("tree.Statements[0] is not *ast.JikaStatement. got: %T", tree.Statements[0])
+	}
+	expect := "(((1 + 2) * 3) > 4)"
+	if got := exprToString(stmnt.Condition); got != expect {
+		t.Fatalf("exprToString(stmnt.Condition) is not %s. got: %s", expect, got)
+	}
+	if len(stmnt.JikaBlock.Statements) != 1 {
+		t.Fatalf("len(stmnt.JikaBlock.Statements) is not 1. got: %d", len(stmnt.JikaBlock.Statements))
+	}
+}
+
+func TestGroupedExpressionError(t *testing.T) {
+	tests := []string{"(1 + 2", "()"}
+	for _, tt := range tests {
+		pars := NewPars(lexer.NewLex(tt))
+		pars.ConstructTree()
+		if len(pars.Errors) == 0 {
+			t.Fatalf("expected parsing error for %q. got none", tt)
+		}
+	}
+}
+
+func TestUnterminatedStringError(t *testing.T) {
+	tests := []string{"buat s = \"halo;", "cetak(`a\nb);", "\"x"}
+	for _, tt := range tests {
+		pars := NewPars(lexer.NewLex(tt))
+		pars.ConstructTree()
+		if len(pars.Errors) == 0 {
+			t.Fatalf("expected parsing error for %q. got none", tt)
+		}
+		if !strings.Contains(pars.Errors[0], "string tidak ditutup di baris 1") {
+			t.Fatalf("pars.Errors[0] does not contain 'string tidak ditutup di baris 1'. got: %s", pars.Errors[0])
+		}
+	}
+}
 
 func TestJikaStatement(t *testing.T) {
 	input := `jika(x > y) {y} lainnya {buat x = 1 + 2 * 2;}`
@@ -365,6 +480,99 @@ func TestJikaStatement(t *testing.T) {
 
 }
 
+func TestSelamaStatement(t *testing.T) {
+	input := `selama(x < 10) { x = x + 1; }`
+	tree := constructTree(t, input)
+
+	if len(tree.Statements) != 1 {
+		t.Fatalf("len(tree.Statements) not 1. got: %d", len(tree.Statements))
+	}
+	stmnt, ok := tree.Statements[0].(*ast.SelamaStatement)
+	if !ok {
+		t.Fatalf("tree.Statements[0] is not *ast.SelamaStatement. got: %T", tree.Statements[0])
+	}
+	if stmnt.Token.Type != token.SELAMA {
+		t.Fatalf("stmnt.Token.Type is not SELAMA. got: %s", stmnt.Token.Literal)
+	}
+	cond, ok := stmnt.Condition.(*ast.InfixExpression)
+	if !ok {
+		t.Fatalf("stmnt.Condition is not *ast.InfixExpression. got: %T", stmnt.Condition)
+	}
+	checkInfix(cond, "(x < 10)")
+
+	if len(stmnt.Body.Statements) != 1 {
+		t.Fatalf("len(stmnt.Body.Statements) is not 1. got: %d", len(stmnt.Body.Statements))
+	}
+	body, ok := stmnt.Body.Statements[0].(*ast.ReassignStatement)
+	if !ok {
+		t.Fatalf("stmnt.Body.Statements[0] is not *ast.ReassignStatement. got: %T", stmnt.Body.Statements[0])
+	}
+	if body.Ident.Value != "x" {
+		t.Fatalf("body.Ident.Value is not 'x'. got: %s", body.Ident.Value)
+	}
+	checkInfix(body.NewValue, "(x + 1)")
+}
+
+func TestUntukStatement(t *testing.T) {
+	input := `untuk (buat i = 0; i < 10; i = i + 1) { henti; lanjut; }`
+	tree := constructTree(t, input)
+
+	if len(tree.Statements) != 1 {
+		t.Fatalf("len(tree.Statements) not 1. got: %d", len(tree.Statements))
+	}
+	stmnt, ok := tree.Statements[0].(*ast.UntukStatement)
+	if !ok {
+		t.Fatalf("tree.Statements[0] is not *ast.UntukStatement. got: %T", tree.Statements[0])
+	}
+	init, ok := stmnt.Init.(*ast.BuatStatement)
+	if !ok {
+		t.Fatalf("stmnt.Init is not *ast.BuatStatement. got: %T", stmnt.Init)
+	}
+	if init.Name.Value != "i" {
+		t.Fatalf("init.Name.Value is not 'i'. got: %s", init.Name.Value)
+	}
+	checkInfix(stmnt.Condition, "(i < 10)")
+	update, ok := stmnt.Update.(*ast.ReassignStatement)
+	if !ok {
+		t.Fatalf("stmnt.Update is not *ast.ReassignStatement. got: %T", stmnt.Update)
+	}
+	checkInfix(update.NewValue, "(i + 1)")
+
+	if len(stmnt.Body.Statements) != 2 {
+		t.Fatalf("len(stmnt.Body.Statements) is not 2. got: %d", len(stmnt.Body.Statements))
+	}
+	if _, ok := stmnt.Body.Statements[0].(*ast.HentiStatement); !ok {
+		t.Fatalf("stmnt.Body.Statements[0] is not *ast.HentiStatement. got: %T", stmnt.Body.Statements[0])
+	}
+	if _, ok := stmnt.Body.Statements[1].(*ast.LanjutStatement); !ok {
+		t.Fatalf("stmnt.Body.Statements[1] is not *ast.LanjutStatement. got: %T", stmnt.Body.Statements[1])
+	}
+}
+
+func TestUntukDalamStatement(t *testing.T) {
+	input := `untuk (x dalam [1, 2]) { x; }`
+	tree := constructTree(t, input)
+
+	if len(tree.Statements) != 1 {
+		t.Fatalf("len(tree.Statements) not 1. got: %d", len(tree.Statements))
+	}
+	stmnt, ok := tree.Statements[0].(*ast.UntukDalamStatement)
+	if !ok {
+		t.Fatalf("tree.Statements[0] is not *ast.UntukDalamStatement. got: %T", tree.Statements[0])
+	}
+	checkIdent(t, stmnt.Ident, "x")
+	arr, ok := stmnt.Iterable.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("stmnt.Iterable is not *ast.ArrayLiteral. got: %T", stmnt.Iterable)
+	}
+	if len(arr.Elements) != 2 {
+		t.Fatalf("len(arr.Elements) is not 2. got: %d", len(arr.Elements))
+	}
+	if len(stmnt.Body.Statements) != 1 {
+		t.Fatalf("len(stmnt.Body.Statements) is not 1. got: %d", len(stmnt.Body.Statements))
+	}
+}
+
 func TestFungsiLiteral(t *testing.T) {
 	input := `fungsi(x, y) { x + y; }`
 	tree := constructTree(t, input)
@@ -420,6 +628,108 @@ func TestCallExpression(t *testing.T) {
 	checkInfix(expr.Arguments[2], "(1 - 2)")
 }
 
+func TestKamusLiteral(t *testing.T) {
+	tests := []struct {
+		input  string
+		keys   []string
+		values []string
+	}{
+		{`{"satu": 1, "dua": 2}`, []string{"satu", "dua"}, []string{"1", "2"}},
+		{`{}`, []string{}, []string{}},
+		{`{1: benar, benar: "ya", "x": 1 + 2 * 3}`, []string{"1", "benar", "x"}, []string{"benar", "ya", "(1 + (2 * 3))"}},
+	}
+	for _, tt := range tests {
+		tree := constructTree(t, tt.input)
+
+		stmnt, ok := tree.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("tree.Statements[0] is not *ast.ExpressionStatement. got: %T", tree.Statements[0])
+		}
+		kamus, ok := stmnt.Expression.(*ast.KamusLiteral)
+		if !ok {
+			t.Fatalf("stmnt.Expression is not *ast.KamusLiteral. got: %T", stmnt.Expression)
+		}
+		if len(kamus.Keys) != len(tt.keys) || len(kamus.Values) != len(tt.values) {
+			t.Fatalf("kamus does not contain %d pair. got: %d keys and %d values", len(tt.keys), len(kamus.Keys), len(kamus.Values))
+		}
+		for i := range tt.keys {
+			if got := exprToString(kamus.Keys[i]); got != tt.keys[i] {
+				t.Fatalf("kamus.Keys[%d] is not %s. got: %s", i, tt.keys[i], got)
+			}
+			if got := exprToString(kamus.Values[i]); got != tt.values[i] {
+				t.Fatalf("kamus.Values[%d] is not %s. got: %s", i, tt.values[i], got)
+			}
+		}
+	}
+}
+
+func TestSliceExpression(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"arr[1:3]", "(arr[1:3])"},
+		{"arr[:2]", "(arr[:2])"},
+		{"arr[1:]", "(arr[1:])"},
+		{"arr[:]", "(arr[:])"},
+		{"arr[a + 1:panjang(arr) - 1]", "(arr[(a + 1):(panjang(arr) - 1)])"},
+		{"arr[0:2][1]", "((arr[0:2])[1])"},
+	}
+	for _, tt := range tests {
+		tree := constructTree(t, tt.input)
+		stmnt, ok := tree.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("tree.Statements[0] is not *ast.ExpressionStatement. got: %T", tree.Statements[0])
+		}
+		if got := exprToString(stmnt.Expression); got != tt.expect {
+			t.Fatalf("expression is not %s. got: %s", tt.expect, got)
+		}
+	}
+}
+
+func TestIndexAssignStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		target   string
+		newValue string
+	}{
+		{"arr[0] = 1;", "(arr[0])", "1"},
+		{`k["a"] = 1 + 2;`, "(k[a])", "(1 + 2)"},
+		{"m[i][j] = x;", "((m[i])[j])", "x"},
+	}
+	for _, tt := range tests {
+		tree := constructTree(t, tt.input)
+		if len(tree.Statements) != 1 {
+			t.Fatalf("tree.Statements does not contain 1 statement. got: %d", len(tree.Statements))
+		}
+		ia, ok := tree.Statements[0].(*ast.IndexAssignStatement)
+		if !ok {
+			t.Fatalf("tree.Statements[0] is not *ast.IndexAssignStatement. got: %T", tree.Statements[0])
+		}
+		if got := exprToString(ia.Target); got != tt.target {
+			t.Fatalf("ia.Target is not %s. got: %s", tt.target, got)
+		}
+		if got := exprToString(ia.NewValue); got != tt.newValue {
+			t.Fatalf("ia.NewValue is not %s. got: %s", tt.newValue, got)
+		}
+	}
+}
+
+func TestEmptyIndex(t *testing.T) {
+	tests := []string{"a[];", "a[] = 3;"}
+	for _, input := range tests {
+		pars := NewPars(lexer.NewLex(input))
+		pars.ConstructTree()
+		if len(pars.Diagnostics) != 1 {
+			t.Fatalf("%q: expected 1 parsing error. got: %q", input, pars.Errors)
+		}
+		d := pars.Diagnostics[0]
+		if d.Code != diagnostic.EKSPRESI_KOSONG || d.Span.Start.Col != 3 {
+			t.Fatalf("%q: wrong diagnostic. got: %+v", input, d)
+		}
+	}
+}
+
 // TODO: too lazy to write the test...
 func TestStringLiteral(t *testing.T) {
 
@@ -502,6 +812,30 @@ func infixTreeToString(e ast.Expression, buffer *bytes.Buffer) {
 	buffer.WriteString(")")
 }
 
+// helper function to turn any expression into a fully parenthesized string
+func exprToString(e ast.Expression) string {
+	switch exp := e.(type) {
+	case *ast.InfixExpression:
+		return "(" + exprToString(exp.Left) + " " + exp.Operator + " " + exprToString(exp.Right) + ")"
+	case *ast.PrefixExpression:
+		return "(" + exp.Operator + exprToString(exp.Right) + ")"
+	case *ast.IndexExpression:
+		return "(" + exprToString(exp.Left) + "[" + exprToString(exp.Index) + "])"
+	case *ast.SliceExpression:
+		return "(" + exprToString(exp.Left) + "[" + exprToString(exp.Start) + ":" + exprToString(exp.End) + "])"
+	case *ast.CallExpression:
+		args := []string{}
+		for _, a := range exp.Arguments {
+			args = append(args, exprToString(a))
+		}
+		return exprToString(exp.Function) + "(" + strings.Join(args, ", ") + ")"
+	case nil:
+		return ""
+	default:
+		return e.TokenLiteral()
+	}
+}
+
 func lookUpBool(lit string) bool {
 	if lit == "benar" {
 		return true
@@ -517,3 +851,181 @@ func constructTree(t *testing.T, input string) *ast.Tree {
 	checkPeekError(t, pars) // check if there error in parsing stage
 	return tree
 }
+
+func TestNodePosition(t *testing.T) {
+	input := "buat x = 1 +\n  foo(2);\ncetak(x)\njika (x) {\n}"
+	tree := constructTree(t, input)
+	if len(tree.Statements) != 3 {
+		t.Fatalf("tree.Statements does not contain 3 statement. got: %d", len(tree.Statements))
+	}
+	buat := tree.Statements[0].(*ast.BuatStatement)
+	infix := buat.Expression.(*ast.InfixExpression)
+	call := infix.Right.(*ast.CallExpression)
+	cetak := tree.Statements[1].(*ast.CetakStatement)
+	jika := tree.Statements[2].(*ast.JikaStatement)
+
+	tests := []struct {
+		name       string
+		node       ast.Node
+		line       int
+		start, end token.Position
+	}{
+		{"buat", buat, 1, token.Position{Offset: 0, Line: 1, Col: 1}, token.Position{Offset: 22, Line: 2, Col: 10}},
+		{"buat.Name", buat.Name, 1, token.Position{Offset: 5, Line: 1, Col: 6}, token.Position{Offset: 6, Line: 1, Col: 7}},
+		{"infix", infix, 1, token.Position{Offset: 9, Line: 1, Col: 10}, token.Position{Offset: 21, Line: 2, Col: 9}},
+		{"call", call, 2, token.Position{Offset: 15, Line: 2, Col: 3}, token.Position{Offset: 21, Line: 2, Col: 9}},
+		{"call.Arguments[0]", call.Arguments[0], 2, token.Position{Offset: 19, Line: 2, Col: 7}, token.Position{Offset: 20, Line: 2, Col: 8}},
+		// the line used to be the lexer lookahead line, which is the next line here
+		{"cetak", cetak, 3, token.Position{Offset: 23, Line: 3, Col: 1}, token.Position{Offset: 31, Line: 3, Col: 9}},
+		{"jika", jika, 4, token.Position{Offset: 32, Line: 4, Col: 1}, token.Position{Offset: 44, Line: 5, Col: 2}},
+		// block start after the '{'
+		{"jika.JikaBlock", jika.JikaBlock, 5, token.Position{Offset: 43, Line: 5, Col: 1}, token.Position{Offset: 44, Line: 5, Col: 2}},
+	}
+	for _, tt := range tests {
+		if tt.node.Line() != tt.line {
+			t.Fatalf("%s.Line() is not %d. got: %d", tt.name, tt.line, tt.node.Line())
+		}
+		if tt.node.Pos().Start != tt.start {
+			t.Fatalf("%s.Pos().Start is not %+v. got: %+v", tt.name, tt.start, tt.node.Pos().Start)
+		}
+		if tt.node.Pos().End != tt.end {
+			t.Fatalf("%s.Pos().End is not %+v. got: %+v", tt.name, tt.end, tt.node.Pos().End)
+		}
+	}
+}
+
+func TestErrorPosition(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"buat x = ;", "ERROR di baris 1, kolom 10:"},
+		{"buat x = 1;\n\n  cetak(x;", "ERROR di baris 3, kolom 10:"},
+		{"jika (x) {\n  1;\n", "ERROR di baris 3, kolom 1:"},
+	}
+	for _, tt := range tests {
+		pars := NewPars(lexer.NewLex(tt.input))
+		pars.ConstructTree()
+		if len(pars.Errors) == 0 {
+			t.Fatalf("expected parsing error for %q. got none", tt.input)
+		}
+		if !strings.HasPrefix(pars.Errors[0], tt.expect) {
+			t.Fatalf("pars.Errors[0] does not start with %q. got: %q", tt.expect, pars.Errors[0])
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		input string
+		code  string
+		hint  string
+		line  int
+		col   int
+	}{
+		{"cetak(1 + 2", diagnostic.TOKEN_HILANG, "mungkin kurang ')'", 1, 12},
+		{"buat x = ;", diagnostic.EKSPRESI_KOSONG, "", 1, 10},
+		{"jika () { 1; }", diagnostic.EKSPRESI_KOSONG, "isi kondisi di antara tanda kurung, contoh: (x > 1)", 1, 7},
+		{"buat s = \"abc;", diagnostic.STRING_TIDAK_DITUTUP, "tutup string dengan tanda kutip yang sama dengan pembukanya", 1, 10},
+	}
+	for _, tt := range tests {
+		pars := NewPars(lexer.NewLex(tt.input))
+		pars.ConstructTree()
+		if len(pars.Diagnostics) != len(pars.Errors) {
+			t.Fatalf("%q: len(pars.Diagnostics) is not %d. got: %d", tt.input, len(pars.Errors), len(pars.Diagnostics))
+		}
+		if len(pars.Diagnostics) == 0 {
+			t.Fatalf("expected parsing error for %q. got none", tt.input)
+		}
+		d := pars.Diagnostics[0]
+		if d.Code != tt.code || d.Hint != tt.hint {
+			t.Fatalf("%q: wrong code or hint. got: %s %q", tt.input, d.Code, d.Hint)
+		}
+		if d.Span.Start.Line != tt.line || d.Span.Start.Col != tt.col {
+			t.Fatalf("%q: diagnostic is not at %d:%d. got: %d:%d", tt.input, tt.line, tt.col, d.Span.Start.Line, d.Span.Start.Col)
+		}
+	}
+}
+
+func TestLineOffset(t *testing.T) {
+	pars := NewPars(lexer.NewLexFromLine("buat x = ;", 7))
+	pars.ConstructTree()
+	if len(pars.Diagnostics) == 0 || pars.Diagnostics[0].Span.Start.Line != 7 {
+		t.Fatalf("diagnostic is not at line 7. got: %+v", pars.Diagnostics)
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input string
+		lines []int // the line of every reported error
+	}{
+		{"buat = ;\ncetak(1);\nbuat y = ;", []int{1, 3}},
+		{"buat x = 1 +\nbuat y = 2 *;\ncetak(y);", []int{2, 2}},
+		{"jika (x {\n\tbuat = 1;\n}\nbuat z = ;", []int{1, 2, 4}},
+		{"buat f = fungsi(a) {\n\tbuat b = ;\n\tkembalikan a +;\n};\nf(1;", []int{2, 3, 5}},
+		{"buat x = [1, 2;\nbuat y = 3;\ncetak(x);", []int{1}},
+		{"buat a = {\"x\": 1 \"y\": 2};\nbuat b = ;", []int{1, 2}},
+		{"untuk (buat i = 0; i < ; i = i + 1) {\n\tcetak(i);\n}\njika () {}", []int{1, 4}},
+		{"}\n1 + ;", []int{1, 2}},
+		{"jika (1 > ) { cetak(1); } lainnya { buat = 3; }", []int{1, 1}},
+		{"selama (x ==) {\n\tbuat = 1;\n\tbuat y = ;\n}", []int{1, 2, 3}},
+		{"jika (x) cetak(1);\nbuat y = ;", []int{1, 2}},
+		{"selama (benar) {\n\tbuat = 1;\n\tbuat k = 2;\n\thenti;\n}\n)", []int{2, 6}},
+		{"buat f = fungsi(a {\n\tkembalikan a;\n};\ncetak(f(1) +);", []int{1, 4}},
+	}
+	for _, tt := range tests {
+		pars := NewPars(lexer.NewLex(tt.input))
+		pars.ConstructTree()
+		lines := []int{}
+		for _, d := range pars.Diagnostics {
+			lines = append(lines, d.Span.Start.Line)
+		}
+		if len(lines) != len(tt.lines) {
+			t.Fatalf("%q: expected %d errors at line %v. got: %v %q", tt.input, len(tt.lines), tt.lines, lines, pars.Errors)
+		}
+		for i := range lines {
+			if lines[i] != tt.lines[i] {
+				t.Fatalf("%q: expected errors at line %v. got: %v", tt.input, tt.lines, lines)
+			}
+		}
+	}
+}
+
+// the parser must always reach EOF, whatever the input is
+func FuzzConstructTree(f *testing.F) {
+	for _, seed := range []string{
+		"buat = ;\ncetak(1);",
+		"jika (x { }",
+		"buat f = fungsi(a { kembalikan a; };",
+		"untuk (buat i = 0; i < ; i = i + 1) { }",
+		"} ) ] ;",
+		"{\"a\": 1 \"b\"}",
+		"[1, 2;",
+		"selama (benar) { henti; lanjut; }",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		pars := NewPars(lexer.NewLex(input))
+		pars.ConstructTree()
+		if len(pars.Errors) != len(pars.Diagnostics) {
+			t.Fatalf("len(pars.Errors) %d is not len(pars.Diagnostics) %d", len(pars.Errors), len(pars.Diagnostics))
+		}
+	})
+}
+
+func TestNumberOutOfRange(t *testing.T) {
+	tests := []string{"buat x = 1e999;", "buat x = 99999999999999999999;"}
+	for _, input := range tests {
+		pars := NewPars(lexer.NewLex(input))
+		pars.ConstructTree()
+		if len(pars.Diagnostics) != 1 {
+			t.Fatalf("%q: expected 1 parsing error. got: %q", input, pars.Errors)
+		}
+		d := pars.Diagnostics[0]
+		if d.Code != diagnostic.ANGKA_TIDAK_VALID || d.Span.Start.Col != 10 {
+			t.Fatalf("%q: wrong diagnostic. got: %+v", input, d)
+		}
+	}
+}
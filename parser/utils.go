@@ -41,6 +41,22 @@ func printStatement(s ast.Statement, b *bytes.Buffer, space int) {
 	case *ast.ReassignStatement:
 		r := s.(*ast.ReassignStatement)
 		printReassignStatement(r, b, space)
+	case *ast.IndexAssignStatement:
+		ia := s.(*ast.IndexAssignStatement)
+		printIndexAssignStatement(ia, b, space)
+	case *ast.SelamaStatement:
+		se := s.(*ast.SelamaStatement)
+		printSelamaStatement(se, b, space)
+	case *ast.UntukStatement:
+		u := s.(*ast.UntukStatement)
+		printUntukStatement(u, b, space)
+	case *ast.UntukDalamStatement:
+		u := s.(*ast.UntukDalamStatement)
+		printUntukDalamStatement(u, b, space)
+	case *ast.HentiStatement:
+		b.WriteString(addSpace(space) + "HENTI_STATEMENT\n")
+	case *ast.LanjutStatement:
+		b.WriteString(addSpace(space) + "LANJUT_STATEMENT\n")
 	}
 	space = 1
 	b.WriteString("\n")
@@ -54,6 +70,9 @@ func printExpression(expr ast.Expression, b *bytes.Buffer, space int) {
 	case *ast.IntegerLiteral:
 		i := expr.(*ast.IntegerLiteral)
 		printIntegerLiteral(i, b, space)
+	case *ast.FloatLiteral:
+		f := expr.(*ast.FloatLiteral)
+		b.WriteString(addSpace(space) + "FLOAT_LITERAL: " + f.Token.Literal + "\n")
 	case *ast.PrefixExpression:
 		p := expr.(*ast.PrefixExpression)
 		printPrefixExpression(p, b, space)
@@ -75,9 +94,15 @@ func printExpression(expr ast.Expression, b *bytes.Buffer, space int) {
 	case *ast.ArrayLiteral:
 		a := expr.(*ast.ArrayLiteral)
 		printArrayLiteral(a, b, space)
-	case *ast.PanjangFungsi:
-		p := expr.(*ast.PanjangFungsi)
-		printPanjangFungsi(p, b, space)
+	case *ast.KamusLiteral:
+		k := expr.(*ast.KamusLiteral)
+		printKamusLiteral(k, b, space)
+	case *ast.IndexExpression:
+		ie := expr.(*ast.IndexExpression)
+		printIndexExpression(ie, b, space)
+	case *ast.SliceExpression:
+		se := expr.(*ast.SliceExpression)
+		printSliceExpression(se, b, space)
 	}
 }
 
@@ -113,6 +138,48 @@ func printJikaStatement(j *ast.JikaStatement, b *bytes.Buffer, space int) {
 	}
 }
 
+func printSelamaStatement(s *ast.SelamaStatement, b *bytes.Buffer, space int) {
+	b.WriteString(addSpace(space) + "SELAMA_STATEMENT:\n")
+	space++
+	b.WriteString(addSpace(space) + "CONDITION:\n")
+	space++
+	printExpression(s.Condition, b, space)
+	b.WriteString(addSpace(space) + "SELAMA_BLOCK: \n")
+	space++
+	printBlockStatement(s.Body, b, space)
+}
+
+func printUntukStatement(u *ast.UntukStatement, b *bytes.Buffer, space int) {
+	b.WriteString(addSpace(space) + "UNTUK_STATEMENT:\n")
+	space++
+	if u.Init != nil {
+		b.WriteString(addSpace(space) + "INIT:\n")
+		printStatement(u.Init, b, space+1)
+		rmBuffNl(b)
+	}
+	if u.Condition != nil {
+		b.WriteString(addSpace(space) + "CONDITION:\n")
+		printExpression(u.Condition, b, space+1)
+	}
+	if u.Update != nil {
+		b.WriteString(addSpace(space) + "UPDATE:\n")
+		printStatement(u.Update, b, space+1)
+		rmBuffNl(b)
+	}
+	b.WriteString(addSpace(space) + "UNTUK_BLOCK: \n")
+	printBlockStatement(u.Body, b, space+1)
+}
+
+func printUntukDalamStatement(u *ast.UntukDalamStatement, b *bytes.Buffer, space int) {
+	b.WriteString(addSpace(space) + "UNTUK_DALAM_STATEMENT:\n")
+	space++
+	printIdent(u.Ident, b, space)
+	b.WriteString(addSpace(space) + "ITERABLE:\n")
+	printExpression(u.Iterable, b, space+1)
+	b.WriteString(addSpace(space) + "UNTUK_BLOCK: \n")
+	printBlockStatement(u.Body, b, space+1)
+}
+
 func printIdent(ident *ast.Identifier, b *bytes.Buffer, space int) {
 	b.WriteString(addSpace(space) + "IDENT: " + ident.Value + "\n")
 }
@@ -150,12 +217,15 @@ func printArrayLiteral(a *ast.ArrayLiteral, b *bytes.Buffer, space int) {
 	}
 }
 
-func printPanjangFungsi(p *ast.PanjangFungsi, b *bytes.Buffer, space int) {
-	b.WriteString(addSpace(space) + "PANJANG_FUNGSI: \n")
+func printKamusLiteral(k *ast.KamusLiteral, b *bytes.Buffer, space int) {
+	b.WriteString(addSpace(space) + "KAMUS_LITERAL: \n")
 	space++
-	b.WriteString(addSpace(space) + "ARGUMENTS: \n")
-	space++
-	printExpression(p.Argument, b, space)
+	for i := range k.Keys {
+		b.WriteString(addSpace(space) + "KEY: \n")
+		printExpression(k.Keys[i], b, space+1)
+		b.WriteString(addSpace(space) + "VALUE: \n")
+		printExpression(k.Values[i], b, space+1)
+	}
 }
 
 func printBooleanLiteral(bl *ast.BooleanLiteral, b *bytes.Buffer, space int) {
@@ -221,6 +291,37 @@ func printReassignStatement(r *ast.ReassignStatement, b *bytes.Buffer, space int
 	printExpression(r.NewValue, b, space)
 }
 
+func printIndexAssignStatement(ia *ast.IndexAssignStatement, b *bytes.Buffer, space int) {
+	b.WriteString(addSpace(space) + "INDEX_ASSIGN_STATEMENT: \n")
+	space++
+	printIndexExpression(ia.Target, b, space)
+	b.WriteString(addSpace(space) + "NEW_VALUE: \n")
+	space++
+	printExpression(ia.NewValue, b, space)
+}
+
+func printIndexExpression(ie *ast.IndexExpression, b *bytes.Buffer, space int) {
+	b.WriteString(addSpace(space) + "INDEX_EXPRESSION: \n")
+	space++
+	printExpression(ie.Left, b, space)
+	b.WriteString(addSpace(space) + "INDEX: \n")
+	printExpression(ie.Index, b, space+1)
+}
+
+func printSliceExpression(se *ast.SliceExpression, b *bytes.Buffer, space int) {
+	b.WriteString(addSpace(space) + "SLICE_EXPRESSION: \n")
+	space++
+	printExpression(se.Left, b, space)
+	if se.Start != nil {
+		b.WriteString(addSpace(space) + "START: \n")
+		printExpression(se.Start, b, space+1)
+	}
+	if se.End != nil {
+		b.WriteString(addSpace(space) + "END: \n")
+		printExpression(se.End, b, space+1)
+	}
+}
+
 func addSpace(r int) string {
 	s := strings.Repeat("  ", r)
 	return s
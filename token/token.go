@@ -5,10 +5,12 @@ type TokenType string // its actually more performant using byte or int
 // define all token the language will have
 const (
 	ILLEGAL TokenType = "ILLEGAL" // token that didn't define in the language
+	ERROR   TokenType = "ERROR"   // malformed token e.g unclosed string. the literal is the error message
 	EOF     TokenType = "EOF"
 
 	IDENT   TokenType = "IDENT" // user-defined. e.g variable name
 	INTEGER TokenType = "INTEGER"
+	FLOAT   TokenType = "FLOAT"
 	STRING  TokenType = "STRING"
 
 	// operator
@@ -18,14 +20,21 @@ const (
 	BANG       TokenType = "!"
 	ASTERISK   TokenType = "*"
 	SLASH      TokenType = "/"
+	MODULO     TokenType = "%"
+	PANGKAT    TokenType = "**"
 	LT         TokenType = "<"
 	GT         TokenType = ">"
+	LT_SAMA    TokenType = "<="
+	GT_SAMA    TokenType = ">="
 	SAMA       TokenType = "=="
 	TIDAK_SAMA TokenType = "!="
+	DAN        TokenType = "&&"
+	ATAU       TokenType = "||"
 
 	// delimiter
 	COMMA     TokenType = ","
 	SEMICOLON TokenType = ";"
+	COLON     TokenType = ":"
 
 	LPAREN   TokenType = "("
 	RPAREN   TokenType = ")"
@@ -43,12 +52,37 @@ const (
 	LAINNYA    TokenType = "LAINNYA"
 	KEMBALIKAN TokenType = "KEMBALIKAN"
 	CETAK      TokenType = "CETAK"
-	PANJANG    TokenType = "PANJANG"
+	SELAMA     TokenType = "SELAMA"
+	UNTUK      TokenType = "UNTUK"
+	DALAM      TokenType = "DALAM"
+	HENTI      TokenType = "HENTI"
+	LANJUT     TokenType = "LANJUT"
+	DIV        TokenType = "DIV" // integer division. '//' is already used for comment
 )
 
 type Token struct {
 	Type    TokenType
 	Literal string
+	Start   Position // the first char of the token
+	End     Position // right after the last char of the token
+}
+
+// Position is a location in the source code
+type Position struct {
+	Offset int // byte offset from the start of the input
+	Line   int // start from 1
+	Col    int // start from 1. counted in character (rune), not byte
+}
+
+// Span is the location of a node or an error in the source code, from Start until End (exclusive)
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// IsZero is true if the span is unknown, e.g node that is not created by the parser
+func (s Span) IsZero() bool {
+	return s.Start.Line == 0
 }
 
 func NewToken(t TokenType, lit string) Token {
@@ -64,7 +98,14 @@ var keywords = map[string]TokenType{
 	"lainnya":    LAINNYA,
 	"kembalikan": KEMBALIKAN,
 	"cetak":      CETAK,
-	"panjang":    PANJANG,
+	"selama":     SELAMA,
+	"untuk":      UNTUK,
+	"dalam":      DALAM,
+	"henti":      HENTI,
+	"lanjut":     LANJUT,
+	"dan":        DAN,
+	"atau":       ATAU,
+	"div":        DIV,
 }
 
 func LookUpIdent(lit string) TokenType {